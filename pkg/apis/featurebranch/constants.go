@@ -2,3 +2,57 @@ package featurebranch
 
 const ApiGroupName = "feature-branch.dmytrostriletskyi.com"
 const ApiGroupVersion = "v1"
+const ApiGroupVersionV2 = "v2"
+
+const (
+	// KeepAnnotation protects a namespace from deletion if its value is "true"
+	KeepAnnotation = ApiGroupName + "/keep"
+	// KeepUntilAnnotation protects a namespace from deletion until the date (YYYY-MM-DD) or the time (RFC 3339)
+	KeepUntilAnnotation = ApiGroupName + "/keep-until"
+)
+
+const (
+	// LastDeployedAtAnnotation is the time (RFC 3339) of a namespace's latest deploy stamped by continuous integration, it
+	// replaces the staleness policy's last activity unless stale feature branches configure another annotation
+	LastDeployedAtAnnotation = ApiGroupName + "/last-deployed-at"
+	// ExpiresAtAnnotation is the time (RFC 3339) a namespace is stale at regardless of stale feature branches' policies
+	ExpiresAtAnnotation = ApiGroupName + "/expires-at"
+)
+
+const (
+	// MarkedForDeletionAtAnnotation is the time (RFC 3339) a namespace is marked for deletion at, removing it rescues
+	// the namespace
+	MarkedForDeletionAtAnnotation = ApiGroupName + "/marked-for-deletion-at"
+	// MarkedForDeletionLabel selects namespaces marked for deletion
+	MarkedForDeletionLabel = ApiGroupName + "/marked-for-deletion"
+	// RescuedAtAnnotation is the time (RFC 3339) a namespace is rescued from deletion at, it counts as a deploy
+	RescuedAtAnnotation = ApiGroupName + "/rescued-at"
+)
+
+const (
+	// HibernatedAtAnnotation is the time (RFC 3339) a namespace is hibernated at
+	HibernatedAtAnnotation = ApiGroupName + "/hibernated-at"
+	// WakeAnnotation restores a hibernated namespace's workloads if its value is "true"
+	WakeAnnotation = ApiGroupName + "/wake"
+	// WokenAtAnnotation is the time (RFC 3339) a namespace is woken at, it counts as a deploy
+	WokenAtAnnotation = ApiGroupName + "/woken-at"
+	// OriginalReplicasAnnotation is the number of a hibernated workload's replicas before hibernation
+	OriginalReplicasAnnotation = ApiGroupName + "/original-replicas"
+	// OriginalSuspendAnnotation is whether a hibernated cron job is suspended before hibernation
+	OriginalSuspendAnnotation = ApiGroupName + "/original-suspend"
+)
+
+// BundleLabel selects config maps containing backups of namespaces
+const BundleLabel = ApiGroupName + "/bundle"
+
+const (
+	// OwnerLabel is the namespace of stale feature branches permitted to manage a namespace, cluster stale feature
+	// branches manage namespaces regardless of it
+	OwnerLabel = ApiGroupName + "/owner"
+)
+
+const (
+	// DurationsAnnotation preserves v2 durations of a stale feature branch that aren't whole days or minutes when it's
+	// converted to v1, so converting it back to v2 doesn't round them
+	DurationsAnnotation = ApiGroupName + "/v2-durations"
+)
@@ -0,0 +1,39 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterStaleFeatureBranch is the Schema for the clusterstalefeaturebranches API. Unlike StaleFeatureBranch, it isn't
+// restricted to namespaces owned by its namespace and is meant to be created by cluster administrators only.
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=clusterstalefeaturebranches,scope=Cluster,shortName=csfb
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedNamespacesCount`
+// +kubebuilder:printcolumn:name="Deleted",type=integer,JSONPath=`.status.deletedNamespacesCount`
+// +kubebuilder:printcolumn:name="Skipped",type=integer,JSONPath=`.status.skippedNamespacesCount`
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.lastRunTime`
+// +kubebuilder:printcolumn:name="Next Run",type=string,JSONPath=`.status.nextRunTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type ClusterStaleFeatureBranch struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   StaleFeatureBranchSpec   `json:"spec,omitempty"`
+	Status StaleFeatureBranchStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterStaleFeatureBranchList contains a list of ClusterStaleFeatureBranch
+type ClusterStaleFeatureBranchList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterStaleFeatureBranch `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterStaleFeatureBranch{}, &ClusterStaleFeatureBranchList{})
+}
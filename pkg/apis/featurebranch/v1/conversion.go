@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+const (
+	day = 24 * time.Hour
+)
+
+// durations are v2 durations preserved by the durations annotation.
+type durations struct {
+	StaleAfter            metav1.Duration `json:"staleAfter"`
+	Interval              metav1.Duration `json:"interval"`
+	DeleteAfter           metav1.Duration `json:"deleteAfter"`
+	GracePeriod           metav1.Duration `json:"gracePeriod"`
+	StuckTerminatingAfter metav1.Duration `json:"stuckTerminatingAfter"`
+}
+
+// ConvertTo converts the stale feature branch to the hub version.
+func (s *StaleFeatureBranch) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*featurebranchv2.StaleFeatureBranch)
+	return convertToV2(s.ObjectMeta, s.Spec, s.Status, &dst.ObjectMeta, &dst.Spec, &dst.Status)
+}
+
+// ConvertFrom converts the stale feature branch from the hub version.
+func (s *StaleFeatureBranch) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*featurebranchv2.StaleFeatureBranch)
+	return convertFromV2(src.ObjectMeta, src.Spec, src.Status, &s.ObjectMeta, &s.Spec, &s.Status)
+}
+
+// ConvertTo converts the cluster stale feature branch to the hub version.
+func (s *ClusterStaleFeatureBranch) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*featurebranchv2.ClusterStaleFeatureBranch)
+	return convertToV2(s.ObjectMeta, s.Spec, s.Status, &dst.ObjectMeta, &dst.Spec, &dst.Status)
+}
+
+// ConvertFrom converts the cluster stale feature branch from the hub version.
+func (s *ClusterStaleFeatureBranch) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*featurebranchv2.ClusterStaleFeatureBranch)
+	return convertFromV2(src.ObjectMeta, src.Spec, src.Status, &s.ObjectMeta, &s.Spec, &s.Status)
+}
+
+// convertToV2 converts days and minutes to durations. Durations preserved by the annotation are used instead if the
+// corresponding days and minutes aren't changed since the conversion from v2. The rest of specifications and the
+// status are the same in both versions.
+func convertToV2(
+	srcMeta metav1.ObjectMeta, srcSpec StaleFeatureBranchSpec, srcStatus StaleFeatureBranchStatus,
+	dstMeta *metav1.ObjectMeta, dstSpec *featurebranchv2.StaleFeatureBranchSpec, dstStatus *featurebranchv2.StaleFeatureBranchStatus,
+) error {
+	if err := convertSame(srcSpec, dstSpec); err != nil {
+		return err
+	}
+
+	if err := convertSame(srcStatus, dstStatus); err != nil {
+		return err
+	}
+
+	dstSpec.StaleAfter = metav1.Duration{Duration: time.Duration(srcSpec.AfterDaysWithoutDeploy) * day}
+	dstSpec.Interval = metav1.Duration{Duration: time.Duration(srcSpec.CheckEveryMinutes) * time.Minute}
+	dstSpec.DeleteAfter = metav1.Duration{Duration: time.Duration(srcSpec.DeleteAfterDaysWithoutDeploy) * day}
+	dstSpec.GracePeriod = metav1.Duration{Duration: time.Duration(srcSpec.GracePeriodMinutes) * time.Minute}
+	dstSpec.StuckTerminatingAfter = metav1.Duration{Duration: time.Duration(srcSpec.StuckTerminatingMinutes) * time.Minute}
+
+	*dstMeta = *srcMeta.DeepCopy()
+
+	preserved, isPreserved := dstMeta.Annotations[featurebranch.DurationsAnnotation]
+
+	if !isPreserved {
+		return nil
+	}
+
+	delete(dstMeta.Annotations, featurebranch.DurationsAnnotation)
+
+	var preservedDurations durations
+
+	if err := json.Unmarshal([]byte(preserved), &preservedDurations); err != nil {
+		return err
+	}
+
+	if toDays(preservedDurations.StaleAfter) == srcSpec.AfterDaysWithoutDeploy {
+		dstSpec.StaleAfter = preservedDurations.StaleAfter
+	}
+
+	if toMinutes(preservedDurations.Interval) == srcSpec.CheckEveryMinutes {
+		dstSpec.Interval = preservedDurations.Interval
+	}
+
+	if toDays(preservedDurations.DeleteAfter) == srcSpec.DeleteAfterDaysWithoutDeploy {
+		dstSpec.DeleteAfter = preservedDurations.DeleteAfter
+	}
+
+	if toMinutes(preservedDurations.GracePeriod) == srcSpec.GracePeriodMinutes {
+		dstSpec.GracePeriod = preservedDurations.GracePeriod
+	}
+
+	if toMinutes(preservedDurations.StuckTerminatingAfter) == srcSpec.StuckTerminatingMinutes {
+		dstSpec.StuckTerminatingAfter = preservedDurations.StuckTerminatingAfter
+	}
+
+	return nil
+}
+
+// convertFromV2 converts durations to days and minutes rounding them up, so namespaces aren't deleted earlier. If any
+// of durations isn't whole days or minutes, durations are preserved by the annotation.
+func convertFromV2(
+	srcMeta metav1.ObjectMeta, srcSpec featurebranchv2.StaleFeatureBranchSpec, srcStatus featurebranchv2.StaleFeatureBranchStatus,
+	dstMeta *metav1.ObjectMeta, dstSpec *StaleFeatureBranchSpec, dstStatus *StaleFeatureBranchStatus,
+) error {
+	if err := convertSame(srcSpec, dstSpec); err != nil {
+		return err
+	}
+
+	if err := convertSame(srcStatus, dstStatus); err != nil {
+		return err
+	}
+
+	dstSpec.AfterDaysWithoutDeploy = toDays(srcSpec.StaleAfter)
+	dstSpec.CheckEveryMinutes = toMinutes(srcSpec.Interval)
+	dstSpec.DeleteAfterDaysWithoutDeploy = toDays(srcSpec.DeleteAfter)
+	dstSpec.GracePeriodMinutes = toMinutes(srcSpec.GracePeriod)
+	dstSpec.StuckTerminatingMinutes = toMinutes(srcSpec.StuckTerminatingAfter)
+
+	*dstMeta = *srcMeta.DeepCopy()
+
+	isRounded := srcSpec.StaleAfter.Duration%day != 0 ||
+		srcSpec.Interval.Duration%time.Minute != 0 ||
+		srcSpec.DeleteAfter.Duration%day != 0 ||
+		srcSpec.GracePeriod.Duration%time.Minute != 0 ||
+		srcSpec.StuckTerminatingAfter.Duration%time.Minute != 0
+
+	if !isRounded {
+		delete(dstMeta.Annotations, featurebranch.DurationsAnnotation)
+		return nil
+	}
+
+	preserved, err := json.Marshal(durations{
+		StaleAfter:            srcSpec.StaleAfter,
+		Interval:              srcSpec.Interval,
+		DeleteAfter:           srcSpec.DeleteAfter,
+		GracePeriod:           srcSpec.GracePeriod,
+		StuckTerminatingAfter: srcSpec.StuckTerminatingAfter,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if dstMeta.Annotations == nil {
+		dstMeta.Annotations = make(map[string]string)
+	}
+
+	dstMeta.Annotations[featurebranch.DurationsAnnotation] = string(preserved)
+
+	return nil
+}
+
+// convertSame converts specifications or a status between versions by their fields that are the same in both of them.
+func convertSame(src interface{}, dst interface{}) error {
+	content, err := json.Marshal(src)
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, dst)
+}
+
+func toDays(duration metav1.Duration) int {
+	return int((duration.Duration + day - 1) / day)
+}
+
+func toMinutes(duration metav1.Duration) int {
+	return int((duration.Duration + time.Minute - 1) / time.Minute)
+}
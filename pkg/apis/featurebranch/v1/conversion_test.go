@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: convert a stale feature branch from v1 to v2.
+// Where: thresholds are days and minutes.
+// Expected: thresholds are converted to durations, the rest of specifications and the status are kept.
+func TestStaleFeatureBranchConvertTo(t *testing.T) {
+	// Set up data for tests.
+	staleFeatureBranch := &StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "team-a"},
+		Spec: StaleFeatureBranchSpec{
+			NamespaceSubstring:           "-pr-",
+			AfterDaysWithoutDeploy:       3,
+			CheckEveryMinutes:            15,
+			Action:                       HibernateActionType,
+			DeleteAfterDaysWithoutDeploy: 14,
+			GracePeriodMinutes:           60,
+			StuckTerminatingMinutes:      30,
+			Backup:                       &BackupSpec{Sink: ConfigMapBackupSinkType},
+		},
+		Status: StaleFeatureBranchStatus{
+			DeletedNamespaces: []string{"project-pr-1"},
+		},
+	}
+
+	hub := &featurebranchv2.StaleFeatureBranch{}
+
+	// Testing.
+	if err := staleFeatureBranch.ConvertTo(hub); err != nil {
+		t.Fatalf("An error occurred while converting a stale feature branch: (%v)", err)
+	}
+
+	assert.Equal(t, staleFeatureBranch.ObjectMeta, hub.ObjectMeta, "Metadata is kept.")
+	assert.Equal(t, 72*time.Hour, hub.Spec.StaleAfter.Duration, "Days without deploy are converted to hours.")
+	assert.Equal(t, 15*time.Minute, hub.Spec.Interval.Duration, "Check every minutes are converted to an interval.")
+	assert.Equal(t, 336*time.Hour, hub.Spec.DeleteAfter.Duration, "Delete after days are converted to hours.")
+	assert.Equal(t, time.Hour, hub.Spec.GracePeriod.Duration, "Grace period minutes are converted to a duration.")
+	assert.Equal(t, 30*time.Minute, hub.Spec.StuckTerminatingAfter.Duration, "Stuck terminating minutes are converted.")
+	assert.Equal(t, "-pr-", hub.Spec.NamespaceSubstring, "Namespace substring is kept.")
+	assert.Equal(t, featurebranchv2.HibernateActionType, hub.Spec.Action, "Action is kept.")
+	assert.Equal(t, featurebranchv2.ConfigMapBackupSinkType, hub.Spec.Backup.Sink, "Backup is kept.")
+	assert.Equal(t, []string{"project-pr-1"}, hub.Status.DeletedNamespaces, "Status is kept.")
+}
+
+// Case: convert a stale feature branch from v2 to v1 and back.
+// Where: durations aren't whole days and minutes.
+// Expected: v1 thresholds are rounded up, durations are preserved by the annotation and restored by converting back.
+func TestStaleFeatureBranchRoundTrip(t *testing.T) {
+	// Set up data for tests.
+	hub := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "team-a"},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 36 * time.Hour},
+			Interval:           metav1.Duration{Duration: 90 * time.Second},
+			GracePeriod:        metav1.Duration{Duration: 30 * time.Second},
+		},
+	}
+
+	staleFeatureBranch := &StaleFeatureBranch{}
+	convertedHub := &featurebranchv2.StaleFeatureBranch{}
+
+	// Testing.
+	if err := staleFeatureBranch.ConvertFrom(hub); err != nil {
+		t.Fatalf("An error occurred while converting a stale feature branch from v2: (%v)", err)
+	}
+
+	assert.Equal(t, 2, staleFeatureBranch.Spec.AfterDaysWithoutDeploy, "Stale after is rounded up to days.")
+	assert.Equal(t, 2, staleFeatureBranch.Spec.CheckEveryMinutes, "Interval is rounded up to minutes.")
+	assert.Equal(t, 1, staleFeatureBranch.Spec.GracePeriodMinutes, "Grace period is rounded up to minutes.")
+	assert.Contains(t, staleFeatureBranch.Annotations, featurebranch.DurationsAnnotation, "Durations are preserved.")
+	assert.Empty(t, hub.Annotations, "Hub's metadata isn't changed.")
+
+	if err := staleFeatureBranch.ConvertTo(convertedHub); err != nil {
+		t.Fatalf("An error occurred while converting a stale feature branch to v2: (%v)", err)
+	}
+
+	assert.Equal(t, hub.Spec, convertedHub.Spec, "Specifications are the same after the round trip.")
+	assert.NotContains(t, convertedHub.Annotations, featurebranch.DurationsAnnotation, "Annotation isn't kept in v2.")
+}
+
+// Case: convert a cluster stale feature branch from v2 to v1, change it and convert it back.
+// Where: days without deploy are changed in v1.
+// Expected: changed threshold is converted from v1, unchanged one is restored from the annotation.
+func TestClusterStaleFeatureBranchRoundTripChanged(t *testing.T) {
+	// Set up data for tests.
+	hub := &featurebranchv2.ClusterStaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch"},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 36 * time.Hour},
+			Interval:           metav1.Duration{Duration: 90 * time.Second},
+		},
+	}
+
+	clusterStaleFeatureBranch := &ClusterStaleFeatureBranch{}
+	convertedHub := &featurebranchv2.ClusterStaleFeatureBranch{}
+
+	// Testing.
+	if err := clusterStaleFeatureBranch.ConvertFrom(hub); err != nil {
+		t.Fatalf("An error occurred while converting a cluster stale feature branch from v2: (%v)", err)
+	}
+
+	clusterStaleFeatureBranch.Spec.AfterDaysWithoutDeploy = 5
+
+	if err := clusterStaleFeatureBranch.ConvertTo(convertedHub); err != nil {
+		t.Fatalf("An error occurred while converting a cluster stale feature branch to v2: (%v)", err)
+	}
+
+	assert.Equal(t, 120*time.Hour, convertedHub.Spec.StaleAfter.Duration, "Changed threshold is converted from v1.")
+	assert.Equal(t, 90*time.Second, convertedHub.Spec.Interval.Duration, "Unchanged threshold is restored.")
+}
@@ -1,13 +1,257 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// NamespacePatternType is a syntax of a namespace pattern
+type NamespacePatternType string
+
+const (
+	// RegexpNamespacePatternType means a namespace pattern is a regular expression
+	RegexpNamespacePatternType NamespacePatternType = "Regexp"
+	// GlobNamespacePatternType means a namespace pattern is a shell glob
+	GlobNamespacePatternType NamespacePatternType = "Glob"
+)
+
+// ActionType is an action applied to stale feature branches' namespaces
+type ActionType string
+
+const (
+	// DeleteActionType means stale namespaces are deleted
+	DeleteActionType ActionType = "Delete"
+	// HibernateActionType means stale namespaces' workloads are scaled to zero and cron jobs are suspended
+	HibernateActionType ActionType = "Hibernate"
+)
+
+// BackupSinkType is a storage of namespaces' backups
+type BackupSinkType string
+
+const (
+	// ConfigMapBackupSinkType means backups are stored as config maps in the operator's namespace
+	ConfigMapBackupSinkType BackupSinkType = "ConfigMap"
+	// DirectoryBackupSinkType means backups are stored as files under the operator's backup directory (a mounted volume)
+	DirectoryBackupSinkType BackupSinkType = "Directory"
+	// S3BackupSinkType means backups are stored as objects of an S3-compatible storage's bucket
+	S3BackupSinkType BackupSinkType = "S3"
+)
+
+// S3BackupSpec defines an S3-compatible storage of backups
+type S3BackupSpec struct {
+	// +kubebuilder:validation:MinLength=1
+	Endpoint string `json:"endpoint"`
+
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// +kubebuilder:validation:Optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=us-east-1
+	Region string `json:"region,omitempty"`
+
+	// Name of a secret in the stale feature branch's namespace with accessKeyId and secretAccessKey keys
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecret string `json:"credentialsSecret"`
+}
+
+// BackupSpec defines backups of namespaces before deletion
+type BackupSpec struct {
+	// +kubebuilder:validation:Enum=ConfigMap;Directory;S3
+	Sink BackupSinkType `json:"sink"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	IncludeSecrets bool `json:"includeSecrets,omitempty"`
+
+	// Directory relative to the operator's backup directory, the backup directory itself by default
+	// +kubebuilder:validation:Optional
+	Directory string `json:"directory,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	S3 *S3BackupSpec `json:"s3,omitempty"`
+}
+
+// StalenessStrategyType is a signal of a namespace's last activity
+type StalenessStrategyType string
+
+const (
+	// NamespaceAgeStalenessStrategyType means a namespace's last activity is its creation
+	NamespaceAgeStalenessStrategyType StalenessStrategyType = "NamespaceAge"
+	// LastRolloutStalenessStrategyType means a namespace's last activity is the latest rollout of its workloads
+	LastRolloutStalenessStrategyType StalenessStrategyType = "LastRollout"
+	// LastPodStartStalenessStrategyType means a namespace's last activity is the latest start of its pods
+	LastPodStartStalenessStrategyType StalenessStrategyType = "LastPodStart"
+	// AnnotationStalenessStrategyType means a namespace's last activity is the time (RFC 3339) of its annotation
+	AnnotationStalenessStrategyType StalenessStrategyType = "Annotation"
+	// HTTPStalenessStrategyType means a namespace's last activity is returned by an external HTTP endpoint
+	HTTPStalenessStrategyType StalenessStrategyType = "HTTP"
+)
+
+// StalenessCombinationType is a way staleness strategies are combined
+type StalenessCombinationType string
+
+const (
+	// AnyStalenessCombinationType means a namespace is stale if any of strategies finds it stale
+	AnyStalenessCombinationType StalenessCombinationType = "Any"
+	// AllStalenessCombinationType means a namespace is stale if all strategies find it stale
+	AllStalenessCombinationType StalenessCombinationType = "All"
+)
+
+// HTTPStalenessSpec defines an external HTTP endpoint returning a namespace's last activity
+type HTTPStalenessSpec struct {
+	// URL requested with GET, {namespace} is replaced by a namespace's name. Response should be JSON with
+	// lastActivityTime (RFC 3339) and, optionally, reason
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+}
+
+// StalenessStrategySpec defines a signal of a namespace's last activity
+type StalenessStrategySpec struct {
+	// +kubebuilder:validation:Enum=NamespaceAge;LastRollout;LastPodStart;Annotation;HTTP
+	Type StalenessStrategyType `json:"type"`
+
+	// Annotation with a namespace's last activity time, required by Annotation strategy
+	// +kubebuilder:validation:Optional
+	Annotation string `json:"annotation,omitempty"`
+
+	// External endpoint, required by HTTP strategy, allowed for ClusterStaleFeatureBranch only
+	// +kubebuilder:validation:Optional
+	HTTP *HTTPStalenessSpec `json:"http,omitempty"`
+}
+
+// StalenessSpec defines how a namespace's last activity is detected
+type StalenessSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Strategies []StalenessStrategySpec `json:"strategies"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Any;All
+	// +kubebuilder:default=All
+	Combination StalenessCombinationType `json:"combination,omitempty"`
+}
+
+// GitProviderType is a Git hosting provider of pull requests
+type GitProviderType string
+
+const (
+	// GitHubProviderType means pull requests are hosted by GitHub or GitHub Enterprise
+	GitHubProviderType GitProviderType = "GitHub"
+	// GitLabProviderType means merge requests are hosted by GitLab
+	GitLabProviderType GitProviderType = "GitLab"
+	// BitbucketProviderType means pull requests are hosted by Bitbucket Cloud
+	BitbucketProviderType GitProviderType = "Bitbucket"
+)
+
+// PullRequestSpec defines pull requests of feature branches' namespaces on a Git hosting provider
+type PullRequestSpec struct {
+	// +kubebuilder:validation:Enum=GitHub;GitLab;Bitbucket
+	Provider GitProviderType `json:"provider"`
+
+	// Base URL of the provider's REST API for self-hosted instances, the provider's cloud is used by default
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+
+	// Repository's full path, e.g. owner/name of GitHub, group/project of GitLab or workspace/slug of Bitbucket
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Regular expression extracting a pull request's number from a namespace's name by its first capture group
+	// +kubebuilder:validation:MinLength=1
+	NumberPattern string `json:"numberPattern"`
+
+	// Secret with token and, for Bitbucket's app passwords, username keys
+	// +kubebuilder:validation:Optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// NotificationSinkType is a destination of notifications
+type NotificationSinkType string
+
+const (
+	// SlackNotificationSinkType means notifications are posted to a Slack incoming webhook
+	SlackNotificationSinkType NotificationSinkType = "Slack"
+	// TeamsNotificationSinkType means notifications are posted to a Microsoft Teams incoming webhook
+	TeamsNotificationSinkType NotificationSinkType = "Teams"
+	// WebhookNotificationSinkType means notifications are posted as JSON to a generic webhook
+	WebhookNotificationSinkType NotificationSinkType = "Webhook"
+	// SMTPNotificationSinkType means notifications are sent by email
+	SMTPNotificationSinkType NotificationSinkType = "SMTP"
+)
+
+// SMTPNotificationSpec defines an SMTP server and recipients of notifications' emails
+type SMTPNotificationSpec struct {
+	// Address of an SMTP server, e.g. smtp.example.com:587
+	// +kubebuilder:validation:MinLength=1
+	Address string `json:"address"`
+
+	// +kubebuilder:validation:MinLength=1
+	From string `json:"from"`
+
+	// +kubebuilder:validation:MinItems=1
+	To []string `json:"to"`
+}
+
+// NotificationSinkSpec defines a destination of notifications
+type NotificationSinkSpec struct {
+	// +kubebuilder:validation:Enum=Slack;Teams;Webhook;SMTP
+	Type NotificationSinkType `json:"type"`
+
+	// URL of a webhook, it's able to be stored in the url key of the credentials' secret instead
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+
+	// Secret with url key for webhooks or username and password keys for SMTP
+	// +kubebuilder:validation:Optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SMTP *SMTPNotificationSpec `json:"smtp,omitempty"`
+}
+
+// NotificationSpec defines notifications about feature branches' namespaces to be deleted and deleted ones
+type NotificationSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Sinks []NotificationSinkSpec `json:"sinks"`
+
+	// Go template of warnings about namespaces marked for deletion within the grace period
+	// +kubebuilder:validation:Optional
+	WarningTemplate string `json:"warningTemplate,omitempty"`
+
+	// Go template of summaries of namespaces deleted by a run
+	// +kubebuilder:validation:Optional
+	DeletedTemplate string `json:"deletedTemplate,omitempty"`
+}
+
 // StaleFeatureBranchSpec defines the desired state of StaleFeatureBranch
 type StaleFeatureBranchSpec struct {
-	// +kubebuilder:validation:Required
-	NamespaceSubstring string `json:"namespaceSubstring"`
+	// +kubebuilder:validation:Optional
+	NamespaceSubstring string `json:"namespaceSubstring,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	NamespacePattern string `json:"namespacePattern,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Regexp;Glob
+	// +kubebuilder:default=Regexp
+	NamespacePatternType NamespacePatternType `json:"namespacePatternType,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NamespaceAnnotations map[string]string `json:"namespaceAnnotations,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
 
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Minimum=1
@@ -17,10 +261,211 @@ type StaleFeatureBranchSpec struct {
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default=30
 	CheckEveryMinutes int `json:"checkEveryMinutes"`
+
+	// +kubebuilder:validation:Optional
+	Staleness *StalenessSpec `json:"staleness,omitempty"`
+
+	// Annotation with the time (RFC 3339) of a namespace's latest deploy stamped by continuous integration, it replaces
+	// the staleness strategies if a namespace has it. Defaults to feature-branch.dmytrostriletskyi.com/last-deployed-at
+	// +kubebuilder:validation:Optional
+	LastDeployedAtAnnotation string `json:"lastDeployedAtAnnotation,omitempty"`
+
+	// Pull requests of feature branches' namespaces, stale namespaces are deleted only if their pull requests are closed
+	// or merged
+	// +kubebuilder:validation:Optional
+	PullRequest *PullRequestSpec `json:"pullRequest,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Delete;Hibernate
+	// +kubebuilder:default=Delete
+	Action ActionType `json:"action,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	DeleteAfterDaysWithoutDeploy int `json:"deleteAfterDaysWithoutDeploy,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	GracePeriodMinutes int `json:"gracePeriodMinutes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Backup *BackupSpec `json:"backup,omitempty"`
+
+	// Notifications about feature branches' namespaces to be deleted within the grace period and deleted ones
+	// +kubebuilder:validation:Optional
+	Notifications *NotificationSpec `json:"notifications,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxDeletionsPerRun int `json:"maxDeletionsPerRun,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=60
+	StuckTerminatingMinutes int `json:"stuckTerminatingMinutes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	ClearStuckFinalizers bool `json:"clearStuckFinalizers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	DryRun bool `json:"dryRun,omitempty"`
+}
+
+// StaleFeatureBranchConditionType is a type of a stale feature branch's condition
+type StaleFeatureBranchConditionType string
+
+const (
+	// StaleFeatureBranchReady means the last processing of feature branches' namespaces succeeded
+	StaleFeatureBranchReady StaleFeatureBranchConditionType = "Ready"
+	// StaleFeatureBranchDegraded means the last processing of feature branches' namespaces failed
+	StaleFeatureBranchDegraded StaleFeatureBranchConditionType = "Degraded"
+	// StaleFeatureBranchBudgetExceeded means the last processing of feature branches' namespaces stopped deleting them
+	// as the deletion budget is exceeded
+	StaleFeatureBranchBudgetExceeded StaleFeatureBranchConditionType = "BudgetExceeded"
+)
+
+// StaleFeatureBranchCondition defines an observed condition of StaleFeatureBranch
+type StaleFeatureBranchCondition struct {
+	Type   StaleFeatureBranchConditionType `json:"type"`
+	Status corev1.ConditionStatus          `json:"status"`
+
+	// +kubebuilder:validation:Optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Reason string `json:"reason,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// MatchedNamespace defines a namespace matched by StaleFeatureBranch
+type MatchedNamespace struct {
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	Captures map[string]string `json:"captures,omitempty"`
+}
+
+// SkippedNamespace defines a matched namespace that isn't deleted by StaleFeatureBranch
+type SkippedNamespace struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+}
+
+// MarkedNamespace defines a namespace marked for deletion by StaleFeatureBranch
+type MarkedNamespace struct {
+	Name        string      `json:"name"`
+	MarkedAt    metav1.Time `json:"markedAt"`
+	DeleteAfter metav1.Time `json:"deleteAfter"`
+}
+
+// BackedUpNamespace defines a namespace backed up before deletion by StaleFeatureBranch
+type BackedUpNamespace struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+// FailedNamespace defines a namespace StaleFeatureBranch failed to process, it's retried with a backoff
+type FailedNamespace struct {
+	Name       string      `json:"name"`
+	Message    string      `json:"message"`
+	Failures   int         `json:"failures"`
+	RetryAfter metav1.Time `json:"retryAfter"`
+}
+
+// StuckNamespace defines a namespace stuck terminating and what blocks its deletion
+type StuckNamespace struct {
+	Name             string      `json:"name"`
+	TerminatingSince metav1.Time `json:"terminatingSince"`
+
+	// +kubebuilder:validation:Optional
+	Finalizers []string `json:"finalizers,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	RemainingResources []string `json:"remainingResources,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
 }
 
 // StaleFeatureBranchStatus defines the observed state of StaleFeatureBranch
 type StaleFeatureBranchStatus struct {
+	// +kubebuilder:validation:Optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+
+	// Time the first of skipped feature branches' namespaces becomes stale or its grace period ends, the next run is
+	// made at it if it's earlier than scheduled
+	// +kubebuilder:validation:Optional
+	NextStaleTime *metav1.Time `json:"nextStaleTime,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MatchedNamespacesCount int `json:"matchedNamespacesCount"`
+
+	// +kubebuilder:validation:Optional
+	DeletedNamespacesCount int `json:"deletedNamespacesCount"`
+
+	// +kubebuilder:validation:Optional
+	SkippedNamespacesCount int `json:"skippedNamespacesCount"`
+
+	// Counts of skipped namespaces by their reasons
+	// +kubebuilder:validation:Optional
+	SkippedNamespacesReasons map[string]int `json:"skippedNamespacesReasons,omitempty"`
+
+	// First 50 matched namespaces, the rest are only counted
+	// +kubebuilder:validation:Optional
+	MatchedNamespaces []MatchedNamespace `json:"matchedNamespaces,omitempty"`
+
+	// First 50 skipped namespaces, the rest are only counted
+	// +kubebuilder:validation:Optional
+	SkippedNamespaces []SkippedNamespace `json:"skippedNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	MarkedNamespaces []MarkedNamespace `json:"markedNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	HibernatedNamespaces []string `json:"hibernatedNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	BackedUpNamespaces []BackedUpNamespace `json:"backedUpNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	DeletedNamespaces []string `json:"deletedNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	WouldBeDeletedNamespaces []string `json:"wouldBeDeletedNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	StuckNamespaces []StuckNamespace `json:"stuckNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	FailedNamespaces []FailedNamespace `json:"failedNamespaces,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	LastError string `json:"lastError,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Conditions []StaleFeatureBranchCondition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -28,6 +473,13 @@ type StaleFeatureBranchStatus struct {
 // StaleFeatureBranch is the Schema for the stalefeaturebranches API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=stalefeaturebranches,scope=Namespaced,shortName=sfb
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Matched",type=integer,JSONPath=`.status.matchedNamespacesCount`
+// +kubebuilder:printcolumn:name="Deleted",type=integer,JSONPath=`.status.deletedNamespacesCount`
+// +kubebuilder:printcolumn:name="Skipped",type=integer,JSONPath=`.status.skippedNamespacesCount`
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.lastRunTime`
+// +kubebuilder:printcolumn:name="Next Run",type=string,JSONPath=`.status.nextRunTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type StaleFeatureBranch struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,38 @@
+package v2
+
+import (
+	"time"
+)
+
+const (
+	// DefaultInterval is used if neither an interval nor a schedule are specified
+	DefaultInterval = 30 * time.Minute
+	// DefaultStuckTerminatingAfter is used if a stuck terminating duration isn't specified
+	DefaultStuckTerminatingAfter = time.Hour
+	// DefaultS3Region is used if a region of an S3-compatible storage isn't specified
+	DefaultS3Region = "us-east-1"
+)
+
+// SetDefaults fills the stale feature branch's specifications that aren't specified with the same defaults the custom
+// resource definition does, for clients that bypass its defaulting.
+func SetDefaults(spec *StaleFeatureBranchSpec) {
+	if spec.NamespacePatternType == "" {
+		spec.NamespacePatternType = RegexpNamespacePatternType
+	}
+
+	if spec.Interval.Duration == 0 {
+		spec.Interval.Duration = DefaultInterval
+	}
+
+	if spec.Action == "" {
+		spec.Action = DeleteActionType
+	}
+
+	if spec.StuckTerminatingAfter.Duration == 0 {
+		spec.StuckTerminatingAfter.Duration = DefaultStuckTerminatingAfter
+	}
+
+	if spec.Backup != nil && spec.Backup.S3 != nil && spec.Backup.S3.Region == "" {
+		spec.Backup.S3.Region = DefaultS3Region
+	}
+}
@@ -0,0 +1,4 @@
+// Package v2 contains API Schema definitions for the feature-branch v2 API group
+// +k8s:deepcopy-gen=package,register
+// +groupName=feature-branch.dmytrostriletskyi.com
+package v2
@@ -0,0 +1,575 @@
+// +build !ignore_autogenerated
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v2
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackedUpNamespace) DeepCopyInto(out *BackedUpNamespace) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackedUpNamespace.
+func (in *BackedUpNamespace) DeepCopy() *BackedUpNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(BackedUpNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
+func (in *BackupSpec) DeepCopy() *BackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStaleFeatureBranch) DeepCopyInto(out *ClusterStaleFeatureBranch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStaleFeatureBranch.
+func (in *ClusterStaleFeatureBranch) DeepCopy() *ClusterStaleFeatureBranch {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStaleFeatureBranch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStaleFeatureBranch) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStaleFeatureBranchList) DeepCopyInto(out *ClusterStaleFeatureBranchList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterStaleFeatureBranch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStaleFeatureBranchList.
+func (in *ClusterStaleFeatureBranchList) DeepCopy() *ClusterStaleFeatureBranchList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStaleFeatureBranchList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStaleFeatureBranchList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FailedNamespace) DeepCopyInto(out *FailedNamespace) {
+	*out = *in
+	in.RetryAfter.DeepCopyInto(&out.RetryAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedNamespace.
+func (in *FailedNamespace) DeepCopy() *FailedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(FailedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPStalenessSpec) DeepCopyInto(out *HTTPStalenessSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPStalenessSpec.
+func (in *HTTPStalenessSpec) DeepCopy() *HTTPStalenessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPStalenessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MarkedNamespace) DeepCopyInto(out *MarkedNamespace) {
+	*out = *in
+	in.MarkedAt.DeepCopyInto(&out.MarkedAt)
+	in.DeleteAfter.DeepCopyInto(&out.DeleteAfter)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MarkedNamespace.
+func (in *MarkedNamespace) DeepCopy() *MarkedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(MarkedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatchedNamespace) DeepCopyInto(out *MatchedNamespace) {
+	*out = *in
+	if in.Captures != nil {
+		in, out := &in.Captures, &out.Captures
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatchedNamespace.
+func (in *MatchedNamespace) DeepCopy() *MatchedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(MatchedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSinkSpec) DeepCopyInto(out *NotificationSinkSpec) {
+	*out = *in
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(SMTPNotificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSinkSpec.
+func (in *NotificationSinkSpec) DeepCopy() *NotificationSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSpec) DeepCopyInto(out *NotificationSpec) {
+	*out = *in
+	if in.Sinks != nil {
+		in, out := &in.Sinks, &out.Sinks
+		*out = make([]NotificationSinkSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSpec.
+func (in *NotificationSpec) DeepCopy() *NotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PullRequestSpec) DeepCopyInto(out *PullRequestSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullRequestSpec.
+func (in *PullRequestSpec) DeepCopy() *PullRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PullRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupSpec) DeepCopyInto(out *S3BackupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupSpec.
+func (in *S3BackupSpec) DeepCopy() *S3BackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMTPNotificationSpec) DeepCopyInto(out *SMTPNotificationSpec) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMTPNotificationSpec.
+func (in *SMTPNotificationSpec) DeepCopy() *SMTPNotificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SMTPNotificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SkippedNamespace) DeepCopyInto(out *SkippedNamespace) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SkippedNamespace.
+func (in *SkippedNamespace) DeepCopy() *SkippedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(SkippedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleFeatureBranch) DeepCopyInto(out *StaleFeatureBranch) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleFeatureBranch.
+func (in *StaleFeatureBranch) DeepCopy() *StaleFeatureBranch {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleFeatureBranch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StaleFeatureBranch) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleFeatureBranchCondition) DeepCopyInto(out *StaleFeatureBranchCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleFeatureBranchCondition.
+func (in *StaleFeatureBranchCondition) DeepCopy() *StaleFeatureBranchCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleFeatureBranchCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleFeatureBranchList) DeepCopyInto(out *StaleFeatureBranchList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StaleFeatureBranch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleFeatureBranchList.
+func (in *StaleFeatureBranchList) DeepCopy() *StaleFeatureBranchList {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleFeatureBranchList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StaleFeatureBranchList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleFeatureBranchSpec) DeepCopyInto(out *StaleFeatureBranchSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceAnnotations != nil {
+		in, out := &in.NamespaceAnnotations, &out.NamespaceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.StaleAfter = in.StaleAfter
+	out.Interval = in.Interval
+	if in.Staleness != nil {
+		in, out := &in.Staleness, &out.Staleness
+		*out = new(StalenessSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PullRequest != nil {
+		in, out := &in.PullRequest, &out.PullRequest
+		*out = new(PullRequestSpec)
+		**out = **in
+	}
+	out.DeleteAfter = in.DeleteAfter
+	out.GracePeriod = in.GracePeriod
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(BackupSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(NotificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	out.StuckTerminatingAfter = in.StuckTerminatingAfter
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleFeatureBranchSpec.
+func (in *StaleFeatureBranchSpec) DeepCopy() *StaleFeatureBranchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleFeatureBranchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StaleFeatureBranchStatus) DeepCopyInto(out *StaleFeatureBranchStatus) {
+	*out = *in
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRunTime != nil {
+		in, out := &in.NextRunTime, &out.NextRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextStaleTime != nil {
+		in, out := &in.NextStaleTime, &out.NextStaleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.SkippedNamespacesReasons != nil {
+		in, out := &in.SkippedNamespacesReasons, &out.SkippedNamespacesReasons
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchedNamespaces != nil {
+		in, out := &in.MatchedNamespaces, &out.MatchedNamespaces
+		*out = make([]MatchedNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SkippedNamespaces != nil {
+		in, out := &in.SkippedNamespaces, &out.SkippedNamespaces
+		*out = make([]SkippedNamespace, len(*in))
+		copy(*out, *in)
+	}
+	if in.MarkedNamespaces != nil {
+		in, out := &in.MarkedNamespaces, &out.MarkedNamespaces
+		*out = make([]MarkedNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HibernatedNamespaces != nil {
+		in, out := &in.HibernatedNamespaces, &out.HibernatedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BackedUpNamespaces != nil {
+		in, out := &in.BackedUpNamespaces, &out.BackedUpNamespaces
+		*out = make([]BackedUpNamespace, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletedNamespaces != nil {
+		in, out := &in.DeletedNamespaces, &out.DeletedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.WouldBeDeletedNamespaces != nil {
+		in, out := &in.WouldBeDeletedNamespaces, &out.WouldBeDeletedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StuckNamespaces != nil {
+		in, out := &in.StuckNamespaces, &out.StuckNamespaces
+		*out = make([]StuckNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailedNamespaces != nil {
+		in, out := &in.FailedNamespaces, &out.FailedNamespaces
+		*out = make([]FailedNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StaleFeatureBranchCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StaleFeatureBranchStatus.
+func (in *StaleFeatureBranchStatus) DeepCopy() *StaleFeatureBranchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StaleFeatureBranchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StalenessSpec) DeepCopyInto(out *StalenessSpec) {
+	*out = *in
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = make([]StalenessStrategySpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StalenessSpec.
+func (in *StalenessSpec) DeepCopy() *StalenessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StalenessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StalenessStrategySpec) DeepCopyInto(out *StalenessStrategySpec) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPStalenessSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StalenessStrategySpec.
+func (in *StalenessStrategySpec) DeepCopy() *StalenessStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StalenessStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StuckNamespace) DeepCopyInto(out *StuckNamespace) {
+	*out = *in
+	in.TerminatingSince.DeepCopyInto(&out.TerminatingSince)
+	if in.Finalizers != nil {
+		in, out := &in.Finalizers, &out.Finalizers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemainingResources != nil {
+		in, out := &in.RemainingResources, &out.RemainingResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StuckNamespace.
+func (in *StuckNamespace) DeepCopy() *StuckNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(StuckNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,266 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// Exporter exports all namespaced objects of a namespace as a bundle of YAML documents.
+type Exporter interface {
+	Export(namespace string, includeSecrets bool) ([]byte, error)
+}
+
+// Bundler exports namespaces to bundles and restores bundles to namespaces. Resources are discovered from the API
+// server, so custom resources are exported as well.
+type Bundler struct {
+	Discovery discovery.DiscoveryInterface
+	Dynamic   dynamic.Interface
+}
+
+var _ Exporter = &Bundler{}
+
+// NewBundler creates the bundler for the cluster.
+func NewBundler(config *rest.Config) (*Bundler, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bundler{Discovery: discoveryClient, Dynamic: dynamicClient}, nil
+}
+
+// Export exports the namespace's objects. Objects owned by other objects are recreated by their owners, so they aren't
+// exported. Secrets are exported only if included.
+func (b *Bundler) Export(namespace string, includeSecrets bool) ([]byte, error) {
+	resourceLists, err := discovery.ServerPreferredNamespacedResources(b.Discovery)
+
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return nil, err
+	}
+
+	var objects []unstructured.Unstructured
+
+	for _, resourceList := range resourceLists {
+		groupVersion, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, resource := range resourceList.APIResources {
+			groupVersionResource := groupVersion.WithResource(resource.Name)
+
+			if !isExportable(groupVersionResource, resource, includeSecrets) {
+				continue
+			}
+
+			list, err := b.Dynamic.Resource(groupVersionResource).Namespace(namespace).List(
+				context.TODO(), metav1.ListOptions{},
+			)
+
+			if err != nil {
+				return nil, fmt.Errorf("unable to list %s: %v", groupVersionResource, err)
+			}
+
+			for _, object := range list.Items {
+				if len(object.GetOwnerReferences()) != 0 {
+					continue
+				}
+
+				if object.GetKind() == "Secret" && object.Object["type"] == string(corev1.SecretTypeServiceAccountToken) {
+					continue
+				}
+
+				cleanObject(&object)
+				objects = append(objects, object)
+			}
+		}
+	}
+
+	return EncodeBundle(objects)
+}
+
+// Restore creates the bundle's objects in the namespace. The namespace is created if it doesn't exist. Objects that
+// already exist are kept as is.
+func (b *Bundler) Restore(bundle []byte, namespace string) error {
+	objects, err := DecodeBundle(bundle)
+
+	if err != nil {
+		return err
+	}
+
+	groupResources, err := restmapper.GetAPIGroupResources(b.Discovery)
+
+	if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+		return err
+	}
+
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+
+	namespaceObject := unstructured.Unstructured{}
+	namespaceObject.SetAPIVersion("v1")
+	namespaceObject.SetKind("Namespace")
+	namespaceObject.SetName(namespace)
+
+	_, err = b.Dynamic.Resource(corev1.SchemeGroupVersion.WithResource("namespaces")).Create(
+		context.TODO(), &namespaceObject, metav1.CreateOptions{},
+	)
+
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create namespace %s: %v", namespace, err)
+	}
+
+	for index := range objects {
+		object := &objects[index]
+		groupVersionKind := object.GroupVersionKind()
+
+		mapping, err := mapper.RESTMapping(groupVersionKind.GroupKind(), groupVersionKind.Version)
+
+		if err != nil {
+			return fmt.Errorf("unable to restore %s %s: %v", groupVersionKind.Kind, object.GetName(), err)
+		}
+
+		object.SetNamespace(namespace)
+
+		_, err = b.Dynamic.Resource(mapping.Resource).Namespace(namespace).Create(
+			context.TODO(), object, metav1.CreateOptions{},
+		)
+
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("unable to restore %s %s: %v", groupVersionKind.Kind, object.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// EncodeBundle encodes the objects as YAML documents.
+func EncodeBundle(objects []unstructured.Unstructured) ([]byte, error) {
+	var bundle bytes.Buffer
+
+	for _, object := range objects {
+		document, err := yaml.Marshal(object.Object)
+
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.WriteString(bundleDocumentSeparator)
+		bundle.Write(document)
+	}
+
+	return bundle.Bytes(), nil
+}
+
+// DecodeBundle decodes YAML documents of the bundle as objects.
+func DecodeBundle(bundle []byte) ([]unstructured.Unstructured, error) {
+	var objects []unstructured.Unstructured
+
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(bundle), bundleDecoderBufferSize)
+
+	for {
+		var document json.RawMessage
+
+		if err := decoder.Decode(&document); err != nil {
+			if err == io.EOF {
+				return objects, nil
+			}
+
+			return nil, fmt.Errorf("invalid bundle: %v", err)
+		}
+
+		if len(document) == 0 || string(document) == "null" {
+			continue
+		}
+
+		var object unstructured.Unstructured
+
+		if err := object.UnmarshalJSON(document); err != nil {
+			return nil, fmt.Errorf("invalid bundle: %v", err)
+		}
+
+		objects = append(objects, object)
+	}
+}
+
+func isExportable(groupVersionResource schema.GroupVersionResource, resource metav1.APIResource, includeSecrets bool) bool {
+	if !hasVerbs(resource, "list", "create") {
+		return false
+	}
+
+	groupResource := groupVersionResource.GroupResource()
+
+	for _, skippedResource := range skippedResources {
+		if groupResource == skippedResource {
+			return false
+		}
+	}
+
+	if groupResource == secretsResource {
+		return includeSecrets
+	}
+
+	return true
+}
+
+func hasVerbs(resource metav1.APIResource, verbs ...string) bool {
+	for _, verb := range verbs {
+		hasVerb := false
+
+		for _, resourceVerb := range resource.Verbs {
+			if resourceVerb == verb {
+				hasVerb = true
+			}
+		}
+
+		if !hasVerb {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cleanObject removes the object's fields set by the cluster, so the object can be created in another namespace.
+func cleanObject(object *unstructured.Unstructured) {
+	object.SetNamespace("")
+	object.SetUID("")
+	object.SetResourceVersion("")
+	object.SetSelfLink("")
+	object.SetGeneration(0)
+	object.SetCreationTimestamp(metav1.Time{})
+	object.SetManagedFields(nil)
+
+	delete(object.Object, "status")
+
+	switch object.GetKind() {
+	case "Service":
+		if clusterIP, _, _ := unstructured.NestedString(object.Object, "spec", "clusterIP"); clusterIP != corev1.ClusterIPNone {
+			unstructured.RemoveNestedField(object.Object, "spec", "clusterIP")
+		}
+	case "PersistentVolumeClaim":
+		unstructured.RemoveNestedField(object.Object, "spec", "volumeName")
+	}
+}
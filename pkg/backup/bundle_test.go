@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	fakedynamic "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// Case: export a namespace's objects to a bundle.
+// Where: namespace contains a deployment, a replica set owned by it, a service, a secret and an event.
+// Expected: deployment and service are exported without fields set by the cluster, owned objects, secrets and events
+// aren't exported.
+func TestExport(t *testing.T) {
+	// Set up data for tests.
+	bundler := newFakeBundler(
+		newObject("apps/v1", "Deployment", "project-pr-1", "back-end"),
+		withOwner(newObject("apps/v1", "ReplicaSet", "project-pr-1", "back-end-5d4f")),
+		withClusterIP(newObject("v1", "Service", "project-pr-1", "back-end")),
+		newObject("v1", "Secret", "project-pr-1", "database"),
+		newObject("v1", "Event", "project-pr-1", "back-end.1"),
+		newObject("apps/v1", "Deployment", "project-pr-2", "front-end"),
+	)
+
+	// Testing.
+	bundle, err := bundler.Export("project-pr-1", false)
+
+	if err != nil {
+		t.Fatalf("An error occurred while exporting a namespace: (%v)", err)
+	}
+
+	objects, err := DecodeBundle(bundle)
+
+	if err != nil {
+		t.Fatalf("An error occurred while decoding a bundle: (%v)", err)
+	}
+
+	var exportedObjects []string
+
+	for _, object := range objects {
+		exportedObjects = append(exportedObjects, object.GetKind()+"/"+object.GetName())
+
+		assert.Empty(t, object.GetNamespace(), "Namespace isn't exported.")
+		assert.Empty(t, object.GetUID(), "UID isn't exported.")
+		assert.Empty(t, object.GetResourceVersion(), "Resource version isn't exported.")
+		assert.NotContains(t, object.Object, "status", "Status isn't exported.")
+	}
+
+	assert.ElementsMatch(t, []string{"Deployment/back-end", "Service/back-end"}, exportedObjects, "Objects are exported.")
+
+	for _, object := range objects {
+		if object.GetKind() == "Service" {
+			_, hasClusterIP, _ := unstructured.NestedString(object.Object, "spec", "clusterIP")
+			assert.False(t, hasClusterIP, "Service's cluster IP isn't exported.")
+		}
+	}
+
+	bundle, err = bundler.Export("project-pr-1", true)
+
+	if err != nil {
+		t.Fatalf("An error occurred while exporting a namespace: (%v)", err)
+	}
+
+	objects, err = DecodeBundle(bundle)
+
+	if err != nil {
+		t.Fatalf("An error occurred while decoding a bundle: (%v)", err)
+	}
+
+	assert.Equal(t, 3, len(objects), "Secrets are exported if included.")
+}
+
+// Case: restore a bundle to a namespace.
+// Where: namespace doesn't exist.
+// Expected: namespace is created, bundle's objects are created in the namespace.
+func TestRestore(t *testing.T) {
+	// Set up data for tests.
+	bundler := newFakeBundler()
+
+	bundle := []byte(`---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: back-end
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: back-end
+`)
+
+	// Testing.
+	if err := bundler.Restore(bundle, "project-pr-1-restored"); err != nil {
+		t.Fatalf("An error occurred while restoring a bundle: (%v)", err)
+	}
+
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	deployments := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+
+	_, err := bundler.Dynamic.Resource(namespaces).Get(context.TODO(), "project-pr-1-restored", metav1.GetOptions{})
+
+	assert.NoError(t, err, "Namespace is created.")
+
+	deployment, err := bundler.Dynamic.Resource(deployments).Namespace("project-pr-1-restored").Get(
+		context.TODO(), "back-end", metav1.GetOptions{},
+	)
+
+	if err != nil {
+		t.Fatalf("An error occurred while fetching a restored deployment: (%v)", err)
+	}
+
+	replicas, _, _ := unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+
+	assert.Equal(t, int64(3), replicas, "Deployment is restored.")
+
+	assert.NoError(t, bundler.Restore(bundle, "project-pr-1-restored"), "Existing objects are kept on restore.")
+}
+
+func newFakeBundler(objects ...runtime.Object) *Bundler {
+	discovery := &fakediscovery.FakeDiscovery{
+		Fake: &clienttesting.Fake{
+			Resources: []*metav1.APIResourceList{
+				{
+					GroupVersion: "v1",
+					APIResources: []metav1.APIResource{
+						{Name: "namespaces", Kind: "Namespace", Verbs: []string{"create", "get", "list"}},
+						{Name: "services", Kind: "Service", Namespaced: true, Verbs: []string{"create", "get", "list"}},
+						{Name: "secrets", Kind: "Secret", Namespaced: true, Verbs: []string{"create", "get", "list"}},
+						{Name: "events", Kind: "Event", Namespaced: true, Verbs: []string{"create", "get", "list"}},
+						{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}},
+					},
+				},
+				{
+					GroupVersion: "apps/v1",
+					APIResources: []metav1.APIResource{
+						{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"create", "get", "list"}},
+						{Name: "deployments/scale", Kind: "Scale", Namespaced: true, Verbs: []string{"get"}},
+						{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: []string{"create", "get", "list"}},
+					},
+				},
+			},
+		},
+	}
+
+	return &Bundler{
+		Discovery: discovery,
+		Dynamic:   fakedynamic.NewSimpleDynamicClient(runtime.NewScheme(), objects...),
+	}
+}
+
+func newObject(apiVersion string, kind string, namespace string, name string) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	object.SetUID("d9607e19-f88f-11e6-a518-42010a800195")
+	object.SetResourceVersion("42")
+	object.Object["status"] = map[string]interface{}{"observedGeneration": int64(1)}
+
+	return object
+}
+
+func withOwner(object *unstructured.Unstructured) *unstructured.Unstructured {
+	object.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "back-end"}})
+	return object
+}
+
+func withClusterIP(object *unstructured.Unstructured) *unstructured.Unstructured {
+	object.Object["spec"] = map[string]interface{}{"clusterIP": "10.0.0.42"}
+	return object
+}
@@ -0,0 +1,40 @@
+package backup
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	bundleDocumentSeparator = "---\n"
+	bundleDecoderBufferSize = 4096
+)
+
+var secretsResource = schema.GroupResource{Resource: "secrets"}
+
+// skippedResources are recreated by the cluster or are meaningless outside of the original namespace
+var skippedResources = []schema.GroupResource{
+	{Resource: "events"},
+	{Group: "events.k8s.io", Resource: "events"},
+	{Resource: "endpoints"},
+	{Group: "discovery.k8s.io", Resource: "endpointslices"},
+	{Group: "coordination.k8s.io", Resource: "leases"},
+	{Group: "metrics.k8s.io", Resource: "pods"},
+}
+
+const (
+	// ConfigMapBundleKey is a key of the config map's data the bundle is stored by
+	ConfigMapBundleKey = "bundle.yaml"
+	// BundleLabelValue is a value of the bundle label
+	BundleLabelValue = "true"
+
+	bundleFileExtension = ".yaml"
+)
+
+const (
+	defaultS3Region    = "us-east-1"
+	s3Service          = "s3"
+	s3ScopeTerminator  = "aws4_request"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3AmzDateLayout    = "20060102T150405Z"
+	s3DateLayout       = "20060102"
+)
@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Sink stores bundles as objects of the bucket of an S3-compatible storage (AWS S3, MinIO, etc.). Requests are
+// signed by AWS Signature Version 4, the bucket is addressed by the path.
+type S3Sink struct {
+	Endpoint        string
+	Bucket          string
+	Prefix          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	HTTPClient      *http.Client
+}
+
+var _ Sink = &S3Sink{}
+
+func (s *S3Sink) Store(name string, bundle []byte) (string, error) {
+	key := s.key(name)
+
+	if _, err := s.do(http.MethodPut, key, bundle); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.Bucket, key), nil
+}
+
+func (s *S3Sink) Load(name string) ([]byte, error) {
+	return s.do(http.MethodGet, s.key(name), nil)
+}
+
+func (s *S3Sink) key(name string) string {
+	return path.Join(s.Prefix, name+bundleFileExtension)
+}
+
+func (s *S3Sink) do(method string, key string, body []byte) ([]byte, error) {
+	endpoint, err := url.Parse(s.Endpoint)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", s.Endpoint, err)
+	}
+
+	endpoint.Path = path.Join("/", endpoint.Path, s.Bucket, key)
+
+	request, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+
+	if err != nil {
+		return nil, err
+	}
+
+	region := s.Region
+
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	SignRequest(request, body, region, s.AccessKeyID, s.SecretAccessKey, time.Now())
+
+	httpClient := s.HTTPClient
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	response, err := httpClient.Do(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("%s s3://%s/%s: %s: %s", method, s.Bucket, key, response.Status, responseBody)
+	}
+
+	return responseBody, nil
+}
+
+// SignRequest signs the S3 request by AWS Signature Version 4. The host and all the request's headers are signed.
+func SignRequest(request *http.Request, body []byte, region string, accessKeyID string, secretAccessKey string, signTime time.Time) {
+	signTime = signTime.UTC()
+	payloadHash := hashHex(body)
+	amzDate := signTime.Format(s3AmzDateLayout)
+	date := signTime.Format(s3DateLayout)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := request.Host
+
+	if host == "" {
+		host = request.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	var headerNames []string
+
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalURI := request.URL.EscapedPath()
+
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI,
+		strings.Replace(request.URL.Query().Encode(), "+", "%20", -1),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, s3Service, s3ScopeTerminator}, "/")
+
+	stringToSign := strings.Join([]string{
+		s3SigningAlgorithm,
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, s3Service)
+	signingKey = hmacSHA256(signingKey, s3ScopeTerminator)
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm,
+		accessKeyID,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
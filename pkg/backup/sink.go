@@ -0,0 +1,94 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Sink stores bundles by their names and loads them back.
+type Sink interface {
+	// Store stores the bundle and returns its location.
+	Store(name string, bundle []byte) (string, error)
+	// Load loads the bundle stored by its name, e.g. to restore it.
+	Load(name string) ([]byte, error)
+}
+
+// ConfigMapSink stores bundles as config maps in the namespace. Config maps are limited to 1 MiB by the cluster.
+type ConfigMapSink struct {
+	Client    client.Client
+	Namespace string
+}
+
+var _ Sink = &ConfigMapSink{}
+
+func (s *ConfigMapSink) Store(name string, bundle []byte) (string, error) {
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: s.Namespace,
+			Labels: map[string]string{
+				featurebranch.BundleLabel: BundleLabelValue,
+			},
+		},
+		Data: map[string]string{
+			ConfigMapBundleKey: string(bundle),
+		},
+	}
+
+	if err := s.Client.Create(context.TODO(), &configMap); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("configmap/%s/%s", s.Namespace, name), nil
+}
+
+func (s *ConfigMapSink) Load(name string) ([]byte, error) {
+	var configMap corev1.ConfigMap
+
+	if err := s.Client.Get(context.TODO(), types.NamespacedName{Namespace: s.Namespace, Name: name}, &configMap); err != nil {
+		return nil, err
+	}
+
+	bundle, isBundle := configMap.Data[ConfigMapBundleKey]
+
+	if !isBundle {
+		return nil, fmt.Errorf("config map %s/%s doesn't contain %s", s.Namespace, name, ConfigMapBundleKey)
+	}
+
+	return []byte(bundle), nil
+}
+
+// DirectorySink stores bundles as files in the directory, e.g. a mounted persistent volume.
+type DirectorySink struct {
+	Path string
+}
+
+var _ Sink = &DirectorySink{}
+
+func (s *DirectorySink) Store(name string, bundle []byte) (string, error) {
+	if err := os.MkdirAll(s.Path, 0755); err != nil {
+		return "", err
+	}
+
+	bundlePath := filepath.Join(s.Path, name+bundleFileExtension)
+
+	if err := ioutil.WriteFile(bundlePath, bundle, 0644); err != nil {
+		return "", err
+	}
+
+	return bundlePath, nil
+}
+
+func (s *DirectorySink) Load(name string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(s.Path, name+bundleFileExtension))
+}
@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Case: store a bundle to a config map and load it back.
+// Where: config map sink in the operator's namespace.
+// Expected: bundle is stored and loaded back.
+func TestConfigMapSink(t *testing.T) {
+	// Set up data for tests.
+	sink := ConfigMapSink{
+		Client:    fake.NewFakeClientWithScheme(scheme.Scheme),
+		Namespace: "stale-feature-branch-operator",
+	}
+
+	bundle := []byte("---\napiVersion: v1\nkind: ConfigMap\n")
+
+	// Testing.
+	location, err := sink.Store("project-pr-1-20100110-120000", bundle)
+
+	if err != nil {
+		t.Fatalf("An error occurred while storing a bundle: (%v)", err)
+	}
+
+	assert.Equal(t, "configmap/stale-feature-branch-operator/project-pr-1-20100110-120000", location, "Location is returned.")
+
+	loadedBundle, err := sink.Load("project-pr-1-20100110-120000")
+
+	if err != nil {
+		t.Fatalf("An error occurred while loading a bundle: (%v)", err)
+	}
+
+	assert.Equal(t, bundle, loadedBundle, "Bundle is loaded back.")
+}
+
+// Case: store a bundle to a directory and load it back.
+// Where: directory doesn't exist yet.
+// Expected: directory is created, bundle is stored and loaded back.
+func TestDirectorySink(t *testing.T) {
+	// Set up data for tests.
+	directory, err := ioutil.TempDir("", "backups")
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a directory: (%v)", err)
+	}
+
+	defer os.RemoveAll(directory)
+
+	sink := DirectorySink{
+		Path: filepath.Join(directory, "feature-branches"),
+	}
+
+	bundle := []byte("---\napiVersion: v1\nkind: ConfigMap\n")
+
+	// Testing.
+	location, err := sink.Store("project-pr-1-20100110-120000", bundle)
+
+	if err != nil {
+		t.Fatalf("An error occurred while storing a bundle: (%v)", err)
+	}
+
+	assert.Equal(t, filepath.Join(directory, "feature-branches", "project-pr-1-20100110-120000.yaml"), location, "Location is returned.")
+
+	loadedBundle, err := sink.Load("project-pr-1-20100110-120000")
+
+	if err != nil {
+		t.Fatalf("An error occurred while loading a bundle: (%v)", err)
+	}
+
+	assert.Equal(t, bundle, loadedBundle, "Bundle is loaded back.")
+}
@@ -3,4 +3,13 @@ package main
 const (
 	FailedExitCode     = 1
 	WatchAllNamespaces = ""
+	// DefaultMetricsBindAddress is used if METRICS_BIND_ADDRESS environment variable isn't set
+	DefaultMetricsBindAddress = ":8080"
+	// WebhookPort is used to serve admission webhooks if IS_WEBHOOKS_ENABLED environment variable is set
+	WebhookPort = 9443
+	// RestoreCommand re-applies a backup bundle instead of running the operator
+	RestoreCommand        = "restore"
+	ReadFromStandardInput = "-"
+	// S3TimeoutSeconds limits requests of the restore command to S3 sink
+	S3TimeoutSeconds = 60
 )
@@ -1,17 +1,62 @@
 package controllers
 
 import (
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/backup"
 	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers/stalefeaturebranch"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
-func RegisterControllers(manager manager.Manager) error {
+func RegisterControllers(manager manager.Manager, triggers *stalefeaturebranch.RunTriggers, pullRequestStates *stalefeaturebranch.PullRequestStateCache) error {
+	bundler, err := backup.NewBundler(manager.GetConfig())
+
+	if err != nil {
+		return err
+	}
+
+	budget, err := stalefeaturebranch.NewDeletionBudgetFromEnvironment()
+
+	if err != nil {
+		return err
+	}
+
+	if budget != nil {
+		budget.Client = manager.GetClient()
+		budget.APIReader = manager.GetAPIReader()
+
+		if err := budget.Load(manager.GetAPIReader(), time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := stalefeaturebranch.IndexNamespaces(manager.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	batch := stalefeaturebranch.NewNamespaceBatch(manager.GetClient(), triggers, true)
+
 	staleFeatureBranchReconcile := &stalefeaturebranch.ReconcileStaleFeatureBranch{
-		Client: manager.GetClient(),
-		Scheme: manager.GetScheme(),
+		Client:            manager.GetClient(),
+		APIReader:         manager.GetAPIReader(),
+		Scheme:            manager.GetScheme(),
+		Recorder:          manager.GetEventRecorderFor("stale-feature-branch-operator"),
+		Exporter:          bundler,
+		Budget:            budget,
+		Triggers:          triggers,
+		Batch:             batch,
+		PullRequestStates: pullRequestStates,
+	}
+
+	if err := stalefeaturebranch.CreateController(manager, staleFeatureBranchReconcile, triggers, batch); err != nil {
+		return err
+	}
+
+	clusterStaleFeatureBranchReconcile := &stalefeaturebranch.ReconcileClusterStaleFeatureBranch{
+		ReconcileStaleFeatureBranch: staleFeatureBranchReconcile,
 	}
 
-	if err := stalefeaturebranch.CreateController(manager, staleFeatureBranchReconcile); err != nil {
+	if err := stalefeaturebranch.CreateClusterController(manager, clusterStaleFeatureBranchReconcile, triggers, batch); err != nil {
 		return err
 	}
 
@@ -0,0 +1,135 @@
+package stalefeaturebranch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/backup"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidateBackup validates the stale feature branch's backup specifications.
+func ValidateBackup(spec featurebranchv2.StaleFeatureBranchSpec) error {
+	if spec.Backup == nil {
+		return nil
+	}
+
+	switch spec.Backup.Sink {
+	case featurebranchv2.ConfigMapBackupSinkType:
+	case featurebranchv2.DirectoryBackupSinkType:
+		if _, err := GetBackupDirectory("/", spec.Backup.Directory); err != nil {
+			return err
+		}
+	case featurebranchv2.S3BackupSinkType:
+		if spec.Backup.S3 == nil {
+			return fmt.Errorf("backup s3 is required by %s sink", spec.Backup.Sink)
+		}
+	default:
+		return fmt.Errorf("unknown backup sink %q", spec.Backup.Sink)
+	}
+
+	return nil
+}
+
+// BackupNamespace exports the namespace's objects and stores the bundle to the stale feature branch's backup sink.
+// Returns the bundle's location.
+func (r *ReconcileStaleFeatureBranch) BackupNamespace(staleFeatureBranch featurebranchv2.StaleFeatureBranch, namespace corev1.Namespace) (string, error) {
+	if r.Exporter == nil {
+		return "", errors.New("backups aren't supported by the operator")
+	}
+
+	sink, err := r.NewBackupSink(staleFeatureBranch)
+
+	if err != nil {
+		return "", err
+	}
+
+	bundle, err := r.Exporter.Export(namespace.Name, staleFeatureBranch.Spec.Backup.IncludeSecrets)
+
+	if err != nil {
+		return "", err
+	}
+
+	bundleName := fmt.Sprintf("%s-%s", namespace.Name, metav1.Now().UTC().Format(backupTimeLayout))
+
+	return sink.Store(bundleName, bundle)
+}
+
+// NewBackupSink creates the stale feature branch's backup sink. Config maps are stored in the operator's namespace,
+// or in the stale feature branch's namespace if the operator's namespace is unknown. Files are stored under the
+// operator's backup directory. Credentials of cluster stale feature branches are read from the operator's namespace.
+// Requests to S3 time out, so a hanging storage doesn't stall deletion of namespaces.
+func (r *ReconcileStaleFeatureBranch) NewBackupSink(staleFeatureBranch featurebranchv2.StaleFeatureBranch) (backup.Sink, error) {
+	backupSpec := staleFeatureBranch.Spec.Backup
+
+	switch backupSpec.Sink {
+	case featurebranchv2.ConfigMapBackupSinkType:
+		namespace := os.Getenv("OPERATOR_NAMESPACE")
+
+		if namespace == "" {
+			namespace = staleFeatureBranch.Namespace
+		}
+
+		if namespace == "" {
+			return nil, errors.New("operator's namespace is unknown to store backups of a cluster stale feature branch")
+		}
+
+		return &backup.ConfigMapSink{Client: r.Client, Namespace: namespace}, nil
+	case featurebranchv2.DirectoryBackupSinkType:
+		root := os.Getenv("BACKUP_DIRECTORY")
+
+		if root == "" {
+			return nil, errors.New("backups to directories aren't enabled by the operator")
+		}
+
+		directory, err := GetBackupDirectory(root, backupSpec.Directory)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &backup.DirectorySink{Path: directory}, nil
+	case featurebranchv2.S3BackupSinkType:
+		credentials, err := r.getCredentialsSecret(staleFeatureBranch, backupSpec.S3.CredentialsSecret)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch backup credentials: %v", err)
+		}
+
+		return &backup.S3Sink{
+			Endpoint:        backupSpec.S3.Endpoint,
+			Bucket:          backupSpec.S3.Bucket,
+			Prefix:          backupSpec.S3.Prefix,
+			Region:          backupSpec.S3.Region,
+			AccessKeyID:     string(credentials.Data[s3AccessKeyIDSecretKey]),
+			SecretAccessKey: string(credentials.Data[s3SecretAccessKeySecretKey]),
+			HTTPClient:      &http.Client{Timeout: s3TimeoutSeconds * time.Second},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown backup sink %q", backupSpec.Sink)
+}
+
+// GetBackupDirectory resolves the stale feature branch's backup directory under the operator's root directory. The
+// directory is set by tenants, so absolute directories and ones escaping the root are rejected.
+func GetBackupDirectory(root string, directory string) (string, error) {
+	if filepath.IsAbs(directory) {
+		return "", fmt.Errorf("backup directory %q should be relative to the operator's backup directory", directory)
+	}
+
+	cleanDirectory := filepath.Clean(directory)
+
+	if cleanDirectory == ".." || strings.HasPrefix(cleanDirectory, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("backup directory %q escapes the operator's backup directory", directory)
+	}
+
+	return filepath.Join(root, cleanDirectory), nil
+}
@@ -0,0 +1,226 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/backup"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Case: back up stale feature branches before deletion.
+// Where: backups are stored as config maps, the second namespace's export fails.
+// Expected: the first namespace is backed up and deleted, the second namespace isn't deleted.
+func TestBackupNamespaceBeforeDeletion(t *testing.T) {
+	// Set up data for tests.
+	var (
+		reconciler ReconcileStaleFeatureBranch
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+			Backup: &featurebranchv2.BackupSpec{
+				Sink: featurebranchv2.ConfigMapBackupSinkType,
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-2"}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, objects...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+		Exporter: fakeExporter{
+			bundles: map[string]string{"project-pr-1": "---\napiVersion: v1\nkind: ConfigMap\n"},
+		},
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	// Testing.
+	err = reconciler.ProcessNamespaces(staleFeatureBranch, matcher, false)
+
+	assert.Error(t, err, "Failed backup is returned as an error.")
+
+	status := staleFeatureBranch.Status
+
+	assert.Equal(t, []string{"project-pr-1"}, status.DeletedNamespaces, "Backed up namespace is deleted.")
+	assert.Equal(t, 1, len(status.BackedUpNamespaces), "Backed up namespace is recorded to the status.")
+
+	var configMaps corev1.ConfigMapList
+
+	if err := reconciler.Client.List(context.TODO(), &configMaps); err != nil {
+		t.Fatalf("An error occurred while fetching config maps: (%v)", err)
+	}
+
+	assert.Equal(t, 1, len(configMaps.Items), "Bundle is stored as a config map.")
+	assert.Equal(
+		t,
+		"configmap/stale-feature-branch-operator/"+configMaps.Items[0].Name,
+		status.BackedUpNamespaces[0].Location,
+		"Bundle's location is recorded to the status.",
+	)
+
+	var notBackedUpNamespace corev1.Namespace
+
+	assert.NoError(
+		t,
+		reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: "project-pr-2"}, &notBackedUpNamespace),
+		"Namespace that isn't backed up isn't deleted.",
+	)
+}
+
+// Case: create the stale feature branch's S3 backup sink.
+// Where: credentials are stored in the stale feature branch's namespace.
+// Expected: credentials are read from the secret, requests to S3 time out.
+func TestNewBackupSinkS3(t *testing.T) {
+	// Set up data for tests.
+	staleFeatureBranch := featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			Backup: &featurebranchv2.BackupSpec{
+				Sink: featurebranchv2.S3BackupSinkType,
+				S3: &featurebranchv2.S3BackupSpec{
+					Endpoint:          "https://s3.example.com",
+					Bucket:            "backups",
+					CredentialsSecret: "s3",
+				},
+			},
+		},
+	}
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3", Namespace: "stale-feature-branch-operator"},
+		Data:       map[string][]byte{"accessKeyId": []byte("key"), "secretAccessKey": []byte("secret")},
+	}
+
+	reconciler := ReconcileStaleFeatureBranch{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, credentials),
+		Scheme: scheme.Scheme,
+	}
+
+	// Testing.
+	sink, err := reconciler.NewBackupSink(staleFeatureBranch)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the backup sink: (%v)", err)
+	}
+
+	s3Sink, isS3Sink := sink.(*backup.S3Sink)
+
+	if !isS3Sink {
+		t.Fatalf("An error occurred while creating the backup sink: (unexpected sink %T)", sink)
+	}
+
+	assert.Equal(t, "key", s3Sink.AccessKeyID, "Access key is read from the secret.")
+	assert.Equal(t, "secret", s3Sink.SecretAccessKey, "Secret access key is read from the secret.")
+	assert.Equal(t, s3TimeoutSeconds*time.Second, s3Sink.HTTPClient.Timeout, "Requests to S3 time out.")
+}
+
+// Case: validate the stale feature branch's backup specifications.
+// Where: sinks' parameters are absent or the directory escapes the operator's backup directory.
+// Expected: error is returned.
+func TestValidateBackup(t *testing.T) {
+	// Set up data for tests.
+	backups := []*featurebranchv2.BackupSpec{
+		{Sink: featurebranchv2.DirectoryBackupSinkType, Directory: "/etc"},
+		{Sink: featurebranchv2.DirectoryBackupSinkType, Directory: "back-end/../../etc"},
+		{Sink: featurebranchv2.S3BackupSinkType},
+		{Sink: "Tape"},
+	}
+
+	// Testing.
+	assert.NoError(t, ValidateBackup(featurebranchv2.StaleFeatureBranchSpec{}), "Backups are optional.")
+
+	assert.NoError(
+		t,
+		ValidateBackup(featurebranchv2.StaleFeatureBranchSpec{
+			Backup: &featurebranchv2.BackupSpec{Sink: featurebranchv2.DirectoryBackupSinkType},
+		}),
+		"Directory is optional.",
+	)
+
+	for _, backupSpec := range backups {
+		err := ValidateBackup(featurebranchv2.StaleFeatureBranchSpec{Backup: backupSpec})
+		assert.Error(t, err, "Invalid backup specifications aren't accepted.")
+	}
+}
+
+// Case: resolve the stale feature branch's backup directory under the operator's backup directory.
+// Where: directory is empty, relative, absolute or escapes the operator's backup directory.
+// Expected: empty and relative directories are resolved under the operator's backup directory, others are rejected.
+func TestGetBackupDirectory(t *testing.T) {
+	// Set up data for tests.
+	const root = "/var/backups"
+
+	// Testing.
+	directory, err := GetBackupDirectory(root, "")
+	assert.NoError(t, err, "Empty directory is accepted.")
+	assert.Equal(t, root, directory, "Empty directory is the operator's backup directory.")
+
+	directory, err = GetBackupDirectory(root, "back-end/./daily/../weekly")
+	assert.NoError(t, err, "Relative directory is accepted.")
+	assert.Equal(t, "/var/backups/back-end/weekly", directory, "Relative directory is resolved under the root.")
+
+	for _, escapingDirectory := range []string{"/etc", "..", "../etc", "back-end/../../etc"} {
+		_, err := GetBackupDirectory(root, escapingDirectory)
+		assert.Error(t, err, "Directory escaping the operator's backup directory isn't accepted.")
+	}
+}
+
+// fakeExporter exports the namespaces' bundles, other namespaces fail to be exported.
+type fakeExporter struct {
+	bundles map[string]string
+}
+
+var _ backup.Exporter = fakeExporter{}
+
+func (e fakeExporter) Export(namespace string, includeSecrets bool) ([]byte, error) {
+	bundle, isExported := e.bundles[namespace]
+
+	if !isExported {
+		return nil, errors.New("namespaces are forbidden")
+	}
+
+	return []byte(bundle), nil
+}
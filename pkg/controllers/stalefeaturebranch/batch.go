@@ -0,0 +1,319 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NamespaceMatch is a namespace matched by a stale feature branch with the namespace pattern's capture groups.
+type NamespaceMatch struct {
+	Namespace corev1.Namespace
+	Captures  map[string]string
+}
+
+// IndexNamespaces indexes namespaces of the informer cache by their owners' labels, so namespaces of namespaced stale
+// feature branches are listed without going through all of the cluster's namespaces.
+func IndexNamespaces(indexer client.FieldIndexer) error {
+	return indexer.IndexField(context.TODO(), &corev1.Namespace{}, NamespaceOwnerIndexField, func(object runtime.Object) []string {
+		namespace, isNamespace := object.(*corev1.Namespace)
+
+		if !isNamespace {
+			return nil
+		}
+
+		owner, isOwned := namespace.Labels[featurebranch.OwnerLabel]
+
+		if !isOwned {
+			return nil
+		}
+
+		return []string{owner}
+	})
+}
+
+// ListNamespaces lists namespaces page by page, so thousands of namespaces aren't fetched from the API server by
+// a single response. Pages only apply to readers of the API server: the informer cache ignores the limit and returns
+// all namespaces as a single page.
+func ListNamespaces(reader client.Reader, options ...client.ListOption) ([]corev1.Namespace, error) {
+	var (
+		namespaces    []corev1.Namespace
+		continueToken string
+	)
+
+	for {
+		var page corev1.NamespaceList
+
+		pageOptions := append([]client.ListOption{client.Limit(namespaceListPageSize), client.Continue(continueToken)}, options...)
+
+		if err := reader.List(context.TODO(), &page, pageOptions...); err != nil {
+			return nil, err
+		}
+
+		namespaces = append(namespaces, page.Items...)
+		continueToken = page.Continue
+
+		if continueToken == "" {
+			return namespaces, nil
+		}
+	}
+}
+
+// MatchNamespaces returns namespaces matched by the stale feature branch and the number of evaluated namespaces. They
+// are taken from a pass of the namespace batch if it's set, otherwise namespaces are listed for the stale feature
+// branch alone.
+func (r *ReconcileStaleFeatureBranch) MatchNamespaces(staleFeatureBranch featurebranchv2.StaleFeatureBranch, matcher *NamespaceMatcher) ([]NamespaceMatch, int, error) {
+	if r.Batch != nil {
+		return r.Batch.Take(staleFeatureBranch, matcher)
+	}
+
+	namespaces, err := ListNamespaces(r.Client, matcher.ListOptions()...)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matches []NamespaceMatch
+
+	for _, namespace := range namespaces {
+		if captures, isMatched := matcher.Match(namespace); isMatched {
+			matches = append(matches, NamespaceMatch{Namespace: namespace, Captures: captures})
+		}
+	}
+
+	return matches, len(namespaces), nil
+}
+
+// NamespaceBatch evaluates namespaces for all stale feature branches due to run in a single pass over namespaces
+// listed once from the informer cache. Stale feature branches running shortly after, e.g. all of them at the
+// operator's start, by a shared schedule or by a namespace event, take their matched namespaces from the pass instead
+// of listing and matching namespaces again. Passes are forgotten after the max age or once a namespace is changed.
+type NamespaceBatch struct {
+	Client    client.Client
+	Triggers  *RunTriggers
+	IsIndexed bool
+	MaxAge    time.Duration
+
+	mutex    sync.Mutex
+	passTime time.Time
+	results  map[types.NamespacedName]namespaceBatchResult
+}
+
+type namespaceBatchResult struct {
+	generation int64
+	evaluated  int
+	matches    []NamespaceMatch
+}
+
+type namespaceBatchEntry struct {
+	key        types.NamespacedName
+	generation int64
+	matcher    *NamespaceMatcher
+	result     *namespaceBatchResult
+}
+
+// NewNamespaceBatch creates the namespace batch reading from the client. Namespaces are listed by the owner index if
+// it's registered by IndexNamespaces.
+func NewNamespaceBatch(c client.Client, triggers *RunTriggers, isIndexed bool) *NamespaceBatch {
+	return &NamespaceBatch{
+		Client:    c,
+		Triggers:  triggers,
+		IsIndexed: isIndexed,
+		MaxAge:    namespaceBatchMaxAge,
+	}
+}
+
+// Take returns namespaces matched by the stale feature branch and the number of evaluated namespaces from the current
+// pass and forgets them, so each pass's result is used once. A new pass is made if the current one is too old, is
+// forgotten or doesn't have the stale feature branch's current specifications.
+func (b *NamespaceBatch) Take(staleFeatureBranch featurebranchv2.StaleFeatureBranch, matcher *NamespaceMatcher) ([]NamespaceMatch, int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	key := getRunTriggerKey(staleFeatureBranch)
+	result, isPassed := b.results[key]
+
+	if !isPassed || result.generation != staleFeatureBranch.Generation || time.Since(b.passTime) > b.MaxAge {
+		if err := b.pass(staleFeatureBranch, matcher); err != nil {
+			return nil, 0, err
+		}
+
+		result = b.results[key]
+	}
+
+	delete(b.results, key)
+
+	return result.matches, result.evaluated, nil
+}
+
+// Invalidate forgets the current pass, e.g. once a namespace is changed. Nil batch is never passed.
+func (b *NamespaceBatch) Invalidate() {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.results = nil
+}
+
+// pass matches namespaces for the stale feature branch and the rest of stale feature branches whose runs are due or
+// requested. Namespaces are listed once: all of them if any of the stale feature branches is cluster scoped, otherwise
+// only namespaces of their owners.
+func (b *NamespaceBatch) pass(staleFeatureBranch featurebranchv2.StaleFeatureBranch, matcher *NamespaceMatcher) error {
+	passTime := time.Now()
+
+	entries, err := b.listDueEntries(staleFeatureBranch, passTime)
+
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, namespaceBatchEntry{
+		key:        getRunTriggerKey(staleFeatureBranch),
+		generation: staleFeatureBranch.Generation,
+		matcher:    matcher,
+	})
+
+	var (
+		clusterEntries []*namespaceBatchEntry
+		ownerEntries   = make(map[string][]*namespaceBatchEntry)
+	)
+
+	for index := range entries {
+		entry := &entries[index]
+		entry.result = &namespaceBatchResult{generation: entry.generation}
+
+		if entry.key.Namespace == "" {
+			clusterEntries = append(clusterEntries, entry)
+			continue
+		}
+
+		ownerEntries[entry.key.Namespace] = append(ownerEntries[entry.key.Namespace], entry)
+	}
+
+	namespaces, err := b.listNamespaces(len(clusterEntries) > 0, ownerEntries)
+
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		candidates := append(clusterEntries[:len(clusterEntries):len(clusterEntries)], ownerEntries[namespace.Labels[featurebranch.OwnerLabel]]...)
+
+		for _, candidate := range candidates {
+			candidate.result.evaluated++
+
+			if captures, isMatched := candidate.matcher.Match(namespace); isMatched {
+				candidate.result.matches = append(candidate.result.matches, NamespaceMatch{
+					Namespace: *namespace.DeepCopy(),
+					Captures:  captures,
+				})
+			}
+		}
+	}
+
+	b.passTime = passTime
+	b.results = make(map[types.NamespacedName]namespaceBatchResult, len(entries))
+
+	for _, entry := range entries {
+		b.results[entry.key] = *entry.result
+	}
+
+	return nil
+}
+
+// listDueEntries lists stale feature branches other than the given one whose runs are due or requested at the time.
+// Stale feature branches with invalid specifications are left to their own runs.
+func (b *NamespaceBatch) listDueEntries(staleFeatureBranch featurebranchv2.StaleFeatureBranch, passTime time.Time) ([]namespaceBatchEntry, error) {
+	var staleFeatureBranches featurebranchv2.StaleFeatureBranchList
+
+	if err := b.Client.List(context.TODO(), &staleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	var clusterStaleFeatureBranches featurebranchv2.ClusterStaleFeatureBranchList
+
+	if err := b.Client.List(context.TODO(), &clusterStaleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	candidates := staleFeatureBranches.Items
+
+	for _, clusterStaleFeatureBranch := range clusterStaleFeatureBranches.Items {
+		candidates = append(candidates, NewClusterScopedStaleFeatureBranch(clusterStaleFeatureBranch))
+	}
+
+	excludedKey := getRunTriggerKey(staleFeatureBranch)
+
+	var entries []namespaceBatchEntry
+
+	for _, candidate := range candidates {
+		key := getRunTriggerKey(candidate)
+
+		if key == excludedKey {
+			continue
+		}
+
+		runSchedule, err := NewRunSchedule(candidate.Spec)
+
+		if err != nil {
+			continue
+		}
+
+		_, isRunDue := GetDueRunTime(candidate, runSchedule, passTime)
+
+		if !isRunDue && !b.Triggers.IsRequested(candidate) {
+			continue
+		}
+
+		matcher, err := NewNamespaceMatcher(candidate.Spec)
+
+		if err == nil && !IsClusterScoped(candidate) {
+			err = matcher.RestrictToOwner(candidate.Namespace)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, namespaceBatchEntry{key: key, generation: candidate.Generation, matcher: matcher})
+	}
+
+	return entries, nil
+}
+
+func (b *NamespaceBatch) listNamespaces(isAllListed bool, ownerEntries map[string][]*namespaceBatchEntry) ([]corev1.Namespace, error) {
+	if isAllListed {
+		return ListNamespaces(b.Client)
+	}
+
+	var namespaces []corev1.Namespace
+
+	for owner := range ownerEntries {
+		var ownerOption client.ListOption = client.MatchingLabels{featurebranch.OwnerLabel: owner}
+
+		if b.IsIndexed {
+			ownerOption = client.MatchingFields{NamespaceOwnerIndexField: owner}
+		}
+
+		ownedNamespaces, err := ListNamespaces(b.Client, ownerOption)
+
+		if err != nil {
+			return nil, err
+		}
+
+		namespaces = append(namespaces, ownedNamespaces...)
+	}
+
+	return namespaces, nil
+}
@@ -0,0 +1,347 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// namespaceListCountingClient counts lists of namespaces made through the client.
+type namespaceListCountingClient struct {
+	client.Client
+	namespaceLists int
+}
+
+func (c *namespaceListCountingClient) List(ctx context.Context, list runtime.Object, options ...client.ListOption) error {
+	if _, isNamespaceList := list.(*corev1.NamespaceList); isNamespaceList {
+		c.namespaceLists++
+	}
+
+	return c.Client.List(ctx, list, options...)
+}
+
+func newBatchScheme(t testing.TB) *runtime.Scheme {
+	s := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("An error occurred while registering Kubernetes types: (%v)", err)
+	}
+
+	if err := featurebranchv2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("An error occurred while registering stale feature branch types: (%v)", err)
+	}
+
+	return s
+}
+
+func newBatchNamespace(name string, owner string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{featurebranch.OwnerLabel: owner},
+		},
+	}
+}
+
+// Case: match namespaces of stale feature branches by the namespace batch.
+// Where: two stale feature branches of different owners are due, a cluster stale feature branch ran a minute ago.
+// Expected: namespaces of both due stale feature branches are matched by a single pass listing only their owners'
+// namespaces, the second stale feature branch takes its namespaces from the pass, a changed namespace invalidates it.
+func TestNamespaceBatchTake(t *testing.T) {
+	// Set up data for tests.
+	lastRunTime := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	backEnd := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "back-end"},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: time.Hour},
+			Interval:           metav1.Duration{Duration: 30 * time.Minute},
+		},
+	}
+
+	frontEnd := backEnd.DeepCopy()
+	frontEnd.Namespace = "front-end"
+
+	clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-stale-feature-branch"},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: time.Hour},
+			Interval:           metav1.Duration{Duration: 30 * time.Minute},
+		},
+		Status: featurebranchv2.StaleFeatureBranchStatus{LastRunTime: &lastRunTime},
+	}
+
+	objects := []runtime.Object{
+		backEnd,
+		frontEnd,
+		clusterStaleFeatureBranch,
+		newBatchNamespace("back-end-pr-1", "back-end"),
+		newBatchNamespace("back-end-staging", "back-end"),
+		newBatchNamespace("front-end-pr-1", "front-end"),
+		newBatchNamespace("payments-pr-1", "payments"),
+	}
+
+	countingClient := &namespaceListCountingClient{Client: fake.NewFakeClientWithScheme(newBatchScheme(t), objects...)}
+	batch := NewNamespaceBatch(countingClient, nil, false)
+
+	newMatcher := func(staleFeatureBranch *featurebranchv2.StaleFeatureBranch) *NamespaceMatcher {
+		matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+		if err == nil {
+			err = matcher.RestrictToOwner(staleFeatureBranch.Namespace)
+		}
+
+		if err != nil {
+			t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+		}
+
+		return matcher
+	}
+
+	names := func(matches []NamespaceMatch) []string {
+		var matchedNames []string
+
+		for _, match := range matches {
+			matchedNames = append(matchedNames, match.Namespace.Name)
+		}
+
+		return matchedNames
+	}
+
+	// Testing.
+	backEndMatches, backEndEvaluated, err := batch.Take(*backEnd, newMatcher(backEnd))
+
+	if err != nil {
+		t.Fatalf("An error occurred while matching namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, []string{"back-end-pr-1"}, names(backEndMatches), "Namespace of the owner is matched.")
+	assert.Equal(t, 2, backEndEvaluated, "Only namespaces of the owner are evaluated.")
+	assert.Equal(t, 2, countingClient.namespaceLists, "Namespaces of each due owner are listed once.")
+
+	frontEndMatches, _, err := batch.Take(*frontEnd, newMatcher(frontEnd))
+
+	if err != nil {
+		t.Fatalf("An error occurred while matching namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, []string{"front-end-pr-1"}, names(frontEndMatches), "Namespace of the other owner is matched.")
+	assert.Equal(t, 2, countingClient.namespaceLists, "Namespaces of the due stale feature branch are taken from the pass.")
+
+	batch.Invalidate()
+
+	if _, _, err := batch.Take(*frontEnd, newMatcher(frontEnd)); err != nil {
+		t.Fatalf("An error occurred while matching namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, 4, countingClient.namespaceLists, "Namespaces of due owners are listed again once the pass is invalidated.")
+}
+
+// Case: list namespaces page by page.
+// Where: reader returns namespaces by pages of two.
+// Expected: all pages are listed with the continue tokens of the previous ones.
+func TestListNamespacesPages(t *testing.T) {
+	// Set up data for tests.
+	reader := &pagedNamespaceReader{pages: [][]string{{"project-pr-1", "project-pr-2"}, {"project-pr-3"}}}
+
+	// Testing.
+	namespaces, err := ListNamespaces(reader)
+
+	if err != nil {
+		t.Fatalf("An error occurred while listing namespaces: (%v)", err)
+	}
+
+	var names []string
+
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+
+	assert.Equal(t, []string{"project-pr-1", "project-pr-2", "project-pr-3"}, names, "Namespaces of all pages are listed.")
+	assert.Equal(t, []string{"", "1"}, reader.continueTokens, "Pages are requested by the continue tokens.")
+}
+
+// pagedNamespaceReader returns namespaces by pages the way the API server does with limits.
+type pagedNamespaceReader struct {
+	client.Reader
+	pages          [][]string
+	continueTokens []string
+}
+
+func (r *pagedNamespaceReader) List(_ context.Context, list runtime.Object, options ...client.ListOption) error {
+	listOptions := client.ListOptions{}
+	listOptions.ApplyOptions(options)
+
+	r.continueTokens = append(r.continueTokens, listOptions.Continue)
+
+	page := 0
+	fmt.Sscan(listOptions.Continue, &page)
+
+	namespaces := list.(*corev1.NamespaceList)
+
+	for _, name := range r.pages[page] {
+		namespaces.Items = append(namespaces.Items, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	if page+1 < len(r.pages) {
+		namespaces.Continue = fmt.Sprint(page + 1)
+	}
+
+	return nil
+}
+
+// benchmarkNamespaceMatching matches thousands of namespaces for dozens of due cluster stale feature branches.
+func benchmarkNamespaceMatching(b *testing.B, isBatched bool) {
+	const (
+		namespacesCount           = 5000
+		staleFeatureBranchesCount = 20
+	)
+
+	var (
+		objects              []runtime.Object
+		staleFeatureBranches []featurebranchv2.StaleFeatureBranch
+		matchers             []*NamespaceMatcher
+	)
+
+	for index := 0; index < namespacesCount; index++ {
+		team := index % staleFeatureBranchesCount
+		objects = append(objects, newBatchNamespace(fmt.Sprintf("team-%d-pr-%d", team, index), fmt.Sprintf("team-%d", team)))
+	}
+
+	for index := 0; index < staleFeatureBranchesCount; index++ {
+		clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("team-%d", index)},
+			Spec: featurebranchv2.StaleFeatureBranchSpec{
+				NamespacePattern: fmt.Sprintf(`^team-%d-pr-\d+$`, index),
+				StaleAfter:       metav1.Duration{Duration: time.Hour},
+				Interval:         metav1.Duration{Duration: 30 * time.Minute},
+			},
+		}
+
+		matcher, err := NewNamespaceMatcher(clusterStaleFeatureBranch.Spec)
+
+		if err != nil {
+			b.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+		}
+
+		objects = append(objects, clusterStaleFeatureBranch)
+		staleFeatureBranches = append(staleFeatureBranches, NewClusterScopedStaleFeatureBranch(*clusterStaleFeatureBranch))
+		matchers = append(matchers, matcher)
+	}
+
+	reconciler := ReconcileStaleFeatureBranch{Client: fake.NewFakeClientWithScheme(newBatchScheme(b), objects...)}
+
+	if isBatched {
+		reconciler.Batch = NewNamespaceBatch(reconciler.Client, nil, false)
+	}
+
+	b.ResetTimer()
+
+	for iteration := 0; iteration < b.N; iteration++ {
+		reconciler.Batch.Invalidate()
+
+		for index, staleFeatureBranch := range staleFeatureBranches {
+			matches, _, err := reconciler.MatchNamespaces(staleFeatureBranch, matchers[index])
+
+			if err != nil {
+				b.Fatalf("An error occurred while matching namespaces: (%v)", err)
+			}
+
+			if len(matches) != namespacesCount/staleFeatureBranchesCount {
+				b.Fatalf("Unexpected number of matched namespaces: %d", len(matches))
+			}
+		}
+	}
+}
+
+// BenchmarkMatchNamespacesListed lists and matches namespaces for each stale feature branch separately.
+func BenchmarkMatchNamespacesListed(b *testing.B) {
+	benchmarkNamespaceMatching(b, false)
+}
+
+// BenchmarkMatchNamespacesBatched matches namespaces for all stale feature branches by a single pass.
+func BenchmarkMatchNamespacesBatched(b *testing.B) {
+	benchmarkNamespaceMatching(b, true)
+}
+
+// Case: process a namespace matched by two stale feature branches from a single pass of the namespace batch.
+// Where: stale feature branch and cluster stale feature branch are due and match the same stale namespace, the first
+// run deletes it while the second one's result is still in the pass.
+// Expected: the namespace is deleted and counted once, the second run skips it as terminating.
+func TestNamespaceBatchDeletedNamespace(t *testing.T) {
+	// Set up data for tests.
+	spec := featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "-pr-",
+		StaleAfter:         metav1.Duration{Duration: time.Hour},
+		Interval:           metav1.Duration{Duration: 30 * time.Minute},
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "back-end"},
+		Spec:       spec,
+	}
+
+	clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-stale-feature-branch"},
+		Spec:       spec,
+	}
+
+	namespace := newBatchNamespace("back-end-pr-1", "back-end")
+	namespace.CreationTimestamp = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	c := fake.NewFakeClientWithScheme(newBatchScheme(t), staleFeatureBranch, clusterStaleFeatureBranch, namespace)
+
+	reconciler := &ReconcileStaleFeatureBranch{
+		Client:   c,
+		Scheme:   newBatchScheme(t),
+		Recorder: record.NewFakeRecorder(100),
+		Batch:    NewNamespaceBatch(c, nil, false),
+	}
+
+	clusterReconciler := &ReconcileClusterStaleFeatureBranch{ReconcileStaleFeatureBranch: reconciler}
+
+	// Testing.
+	if _, err := reconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "back-end", Name: "stale-feature-branch"}}); err != nil {
+		t.Fatalf("An error occurred while running the stale feature branch: (%v)", err)
+	}
+
+	if _, err := clusterReconciler.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: "cluster-stale-feature-branch"}}); err != nil {
+		t.Fatalf("An error occurred while running the cluster stale feature branch: (%v)", err)
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: "back-end", Name: "stale-feature-branch"}, staleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: "cluster-stale-feature-branch"}, clusterStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the cluster stale feature branch: (%v)", err)
+	}
+
+	assert.Equal(t, 1, staleFeatureBranch.Status.DeletedNamespacesCount, "The first run deletes the namespace.")
+	assert.Equal(t, 0, clusterStaleFeatureBranch.Status.DeletedNamespacesCount, "The second run doesn't delete it again.")
+	assert.Equal(
+		t,
+		[]featurebranchv2.SkippedNamespace{
+			{Name: "back-end-pr-1", Reason: TerminatingSkipReason, Message: "Namespace is already being deleted."},
+		},
+		clusterStaleFeatureBranch.Status.SkippedNamespaces,
+		"The second run skips the deleted namespace as terminating.",
+	)
+}
@@ -0,0 +1,297 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DeletionBudget limits the number of namespaces deleted by all stale feature branches within a sliding time window,
+// so a misconfigured stale feature branch isn't able to delete most of the cluster at once. If the client and the
+// namespace are set, deletions are persisted to a config map, so the window survives restarts of the operator. The API
+// reader re-reads the config map bypassing the informer cache, the client is used if it isn't set.
+type DeletionBudget struct {
+	MaxDeletions int
+	Window       time.Duration
+	Client       client.Client
+	APIReader    client.Reader
+	Namespace    string
+
+	mutex           sync.Mutex
+	deletionTimes   []time.Time
+	resourceVersion string
+}
+
+// NewDeletionBudget creates the deletion budget allowing the number of deletions within the time window.
+func NewDeletionBudget(maxDeletions int, window time.Duration) *DeletionBudget {
+	return &DeletionBudget{
+		MaxDeletions: maxDeletions,
+		Window:       window,
+	}
+}
+
+// NewDeletionBudgetFromEnvironment creates the deletion budget from MAX_DELETIONS_PER_WINDOW and
+// DELETION_WINDOW_MINUTES environment variables. Deletions are unlimited if the maximum isn't set. Deletions are
+// persisted to OPERATOR_NAMESPACE once the client is set.
+func NewDeletionBudgetFromEnvironment() (*DeletionBudget, error) {
+	maxDeletionsAsString := os.Getenv("MAX_DELETIONS_PER_WINDOW")
+
+	if maxDeletionsAsString == "" {
+		return nil, nil
+	}
+
+	maxDeletions, err := strconv.Atoi(maxDeletionsAsString)
+
+	if err != nil || maxDeletions < 1 {
+		return nil, fmt.Errorf("max deletions per window should be a positive integer, got %q", maxDeletionsAsString)
+	}
+
+	windowMinutes := DefaultDeletionWindowMinutes
+
+	if windowMinutesAsString := os.Getenv("DELETION_WINDOW_MINUTES"); windowMinutesAsString != "" {
+		windowMinutes, err = strconv.Atoi(windowMinutesAsString)
+
+		if err != nil || windowMinutes < 1 {
+			return nil, fmt.Errorf("deletion window minutes should be a positive integer, got %q", windowMinutesAsString)
+		}
+	}
+
+	budget := NewDeletionBudget(maxDeletions, time.Duration(windowMinutes)*time.Minute)
+	budget.Namespace = os.Getenv("OPERATOR_NAMESPACE")
+
+	return budget, nil
+}
+
+// IsExceeded reports whether no more deletions are allowed within the time window ending at the given time. Nil budget
+// is never exceeded.
+func (b *DeletionBudget) IsExceeded(now time.Time) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.forget(now)
+
+	return len(b.deletionTimes) >= b.MaxDeletions
+}
+
+// Reserve records the deletion to be made at the given time if the budget isn't exceeded and persists deletions within
+// the time window. The check and the record are made at once, so stale feature branches processed concurrently don't
+// overshoot the budget. The deletion is reserved even if it isn't persisted. Nil budget reserves any deletion.
+func (b *DeletionBudget) Reserve(deletionTime time.Time) (bool, error) {
+	if b == nil {
+		return true, nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.forget(deletionTime)
+
+	if len(b.deletionTimes) >= b.MaxDeletions {
+		return false, nil
+	}
+
+	b.deletionTimes = append(b.deletionTimes, deletionTime)
+
+	return true, b.save()
+}
+
+// Release forgets the deletion reserved at the given time, e.g. once the namespace isn't deleted, and persists deletions
+// within the time window.
+func (b *DeletionBudget) Release(deletionTime time.Time) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for index, reservedTime := range b.deletionTimes {
+		if reservedTime.Equal(deletionTime) {
+			b.deletionTimes = append(b.deletionTimes[:index], b.deletionTimes[index+1:]...)
+			return b.save()
+		}
+	}
+
+	return nil
+}
+
+// Load rebuilds deletions within the time window from the config map, e.g. once the operator is restarted. The reader
+// should bypass the informer cache, as the budget is loaded before the cache is started.
+func (b *DeletionBudget) Load(reader client.Reader, now time.Time) error {
+	if b == nil || b.Client == nil || b.Namespace == "" {
+		return nil
+	}
+
+	var configMap corev1.ConfigMap
+
+	err := reader.Get(context.TODO(), types.NamespacedName{Namespace: b.Namespace, Name: DeletionBudgetConfigMapName}, &configMap)
+
+	if errors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var deletionTimes []time.Time
+
+	for _, deletionTimeAsString := range strings.Fields(configMap.Data[deletionBudgetDeletionTimesKey]) {
+		deletionTime, err := time.Parse(time.RFC3339Nano, deletionTimeAsString)
+
+		if err != nil {
+			return fmt.Errorf("invalid deletion time of config map %s: %q", DeletionBudgetConfigMapName, deletionTimeAsString)
+		}
+
+		deletionTimes = append(deletionTimes, deletionTime)
+	}
+
+	sort.Slice(deletionTimes, func(i, j int) bool {
+		return deletionTimes[i].Before(deletionTimes[j])
+	})
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.deletionTimes = deletionTimes
+	b.resourceVersion = configMap.ResourceVersion
+	b.forget(now)
+
+	return nil
+}
+
+// save persists deletions to the config map, it's created if it doesn't exist. If the config map is modified since it's
+// read, it's re-read to write deletions over its latest version.
+func (b *DeletionBudget) save() error {
+	if b.Client == nil || b.Namespace == "" {
+		return nil
+	}
+
+	deletionTimes := make([]string, 0, len(b.deletionTimes))
+
+	for _, deletionTime := range b.deletionTimes {
+		deletionTimes = append(deletionTimes, deletionTime.UTC().Format(time.RFC3339Nano))
+	}
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            DeletionBudgetConfigMapName,
+			Namespace:       b.Namespace,
+			ResourceVersion: b.resourceVersion,
+		},
+		Data: map[string]string{
+			deletionBudgetDeletionTimesKey: strings.Join(deletionTimes, "\n"),
+		},
+	}
+
+	err := b.write(&configMap)
+
+	if errors.IsConflict(err) {
+		var latestConfigMap corev1.ConfigMap
+
+		if err := b.getAPIReader().Get(
+			context.TODO(),
+			types.NamespacedName{Namespace: b.Namespace, Name: DeletionBudgetConfigMapName},
+			&latestConfigMap,
+		); err != nil {
+			return err
+		}
+
+		b.resourceVersion = latestConfigMap.ResourceVersion
+		configMap.ResourceVersion = latestConfigMap.ResourceVersion
+
+		err = b.write(&configMap)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	b.resourceVersion = configMap.ResourceVersion
+
+	return nil
+}
+
+// write updates the config map or creates it if it doesn't exist.
+func (b *DeletionBudget) write(configMap *corev1.ConfigMap) error {
+	err := b.Client.Update(context.TODO(), configMap)
+
+	if errors.IsNotFound(err) {
+		configMap.ResourceVersion = ""
+		err = b.Client.Create(context.TODO(), configMap)
+	}
+
+	return err
+}
+
+// getAPIReader returns the reader bypassing the informer cache, or the client if it isn't set.
+func (b *DeletionBudget) getAPIReader() client.Reader {
+	if b.APIReader != nil {
+		return b.APIReader
+	}
+
+	return b.Client
+}
+
+// forget removes the deletions made before the time window ending at the given time.
+func (b *DeletionBudget) forget(now time.Time) {
+	windowStart := now.Add(-b.Window)
+	index := 0
+
+	for index < len(b.deletionTimes) && !b.deletionTimes[index].After(windowStart) {
+		index++
+	}
+
+	b.deletionTimes = b.deletionTimes[index:]
+}
+
+// ReserveDeletion reserves one more deletion of the stale feature branch at the given time. It returns the reason and
+// the message if the deletion isn't allowed either by its maximum deletions per run or by the shared deletion budget.
+// Reason is empty if the deletion is reserved, it should be released if the namespace isn't deleted.
+func (r *ReconcileStaleFeatureBranch) ReserveDeletion(staleFeatureBranch featurebranchv2.StaleFeatureBranch, now time.Time) (string, string) {
+	maxDeletionsPerRun := staleFeatureBranch.Spec.MaxDeletionsPerRun
+
+	if maxDeletionsPerRun > 0 && staleFeatureBranch.Status.DeletedNamespacesCount >= maxDeletionsPerRun {
+		return MaxDeletionsPerRunConditionReason, fmt.Sprintf("Max deletions per run (%d) are reached.", maxDeletionsPerRun)
+	}
+
+	isReserved, err := r.Budget.Reserve(now)
+
+	if err != nil {
+		logger.Error(err, "Unable to persist the deletion budget.")
+	}
+
+	if !isReserved {
+		return DeletionWindowConditionReason, fmt.Sprintf(
+			"Max deletions (%d) within %s are reached by all stale feature branches.",
+			r.Budget.MaxDeletions,
+			r.Budget.Window,
+		)
+	}
+
+	return "", ""
+}
+
+// releaseDeletion releases the deletion reserved at the given time once the namespace isn't deleted.
+func (r *ReconcileStaleFeatureBranch) releaseDeletion(deletionTime time.Time) {
+	if err := r.Budget.Release(deletionTime); err != nil {
+		logger.Error(err, "Unable to persist the deletion budget.")
+	}
+}
@@ -0,0 +1,418 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Case: check the deletion budget.
+// Where: two deletions are allowed within an hour.
+// Expected: budget is exceeded by two deletions within the hour and isn't exceeded once the first one leaves the hour.
+func TestDeletionBudget(t *testing.T) {
+	// Set up data for tests.
+	var (
+		budget    = NewDeletionBudget(2, time.Hour)
+		startTime = time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+	)
+
+	// Testing.
+	assert.False(t, budget.IsExceeded(startTime), "Budget isn't exceeded without deletions.")
+
+	for _, deletionTime := range []time.Time{startTime, startTime.Add(30 * time.Minute)} {
+		isReserved, err := budget.Reserve(deletionTime)
+		assert.NoError(t, err, "Deletion is recorded.")
+		assert.True(t, isReserved, "Deletion within the budget is reserved.")
+	}
+
+	isReserved, err := budget.Reserve(startTime.Add(45 * time.Minute))
+	assert.NoError(t, err, "Exceeding deletion isn't recorded.")
+	assert.False(t, isReserved, "Deletion over the budget isn't reserved.")
+
+	assert.True(t, budget.IsExceeded(startTime.Add(59*time.Minute)), "Budget is exceeded within the hour.")
+	assert.False(t, budget.IsExceeded(startTime.Add(61*time.Minute)), "Budget is released once the first deletion leaves the hour.")
+
+	assert.NoError(t, budget.Release(startTime.Add(30*time.Minute)), "Deletion is released.")
+	assert.False(t, budget.IsExceeded(startTime.Add(59*time.Minute)), "Released deletion doesn't count.")
+
+	var unlimitedBudget *DeletionBudget
+
+	isReserved, err = unlimitedBudget.Reserve(startTime)
+	assert.NoError(t, err, "Absent budget records nothing.")
+	assert.True(t, isReserved, "Absent budget reserves any deletion.")
+
+	assert.False(t, unlimitedBudget.IsExceeded(startTime), "Absent budget is never exceeded.")
+}
+
+// Case: reserve deletions of the deletion budget concurrently.
+// Where: five deletions are allowed within an hour, stale feature branches reserve twenty of them at once.
+// Expected: exactly five deletions are reserved.
+func TestDeletionBudgetConcurrentReserve(t *testing.T) {
+	// Set up data for tests.
+	var (
+		budget        = NewDeletionBudget(5, time.Hour)
+		startTime     = time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		reservedCount int32
+		waitGroup     sync.WaitGroup
+	)
+
+	budget.Client = fake.NewFakeClientWithScheme(scheme.Scheme)
+	budget.Namespace = "stale-feature-branch-operator"
+
+	// Testing.
+	for i := 0; i < 20; i++ {
+		waitGroup.Add(1)
+
+		go func(i int) {
+			defer waitGroup.Done()
+
+			if isReserved, _ := budget.Reserve(startTime.Add(time.Duration(i) * time.Second)); isReserved {
+				atomic.AddInt32(&reservedCount, 1)
+			}
+		}(i)
+	}
+
+	waitGroup.Wait()
+
+	assert.Equal(t, int32(5), reservedCount, "Budget isn't overshot by concurrent reservations.")
+}
+
+// Case: persist the deletion budget.
+// Where: config map is modified since the budget wrote it.
+// Expected: config map is re-read and deletions are persisted over its latest version.
+func TestDeletionBudgetConflict(t *testing.T) {
+	// Set up data for tests.
+	var (
+		c         = fake.NewFakeClientWithScheme(scheme.Scheme)
+		startTime = time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		budget    = NewDeletionBudget(2, time.Hour)
+		configMap corev1.ConfigMap
+		key       = types.NamespacedName{Namespace: "stale-feature-branch-operator", Name: DeletionBudgetConfigMapName}
+	)
+
+	budget.Client = c
+	budget.Namespace = key.Namespace
+
+	if _, err := budget.Reserve(startTime); err != nil {
+		t.Fatalf("An error occurred while reserving a deletion: (%v)", err)
+	}
+
+	if err := c.Get(context.TODO(), key, &configMap); err != nil {
+		t.Fatalf("An error occurred while fetching the config map: (%v)", err)
+	}
+
+	configMap.Labels = map[string]string{"modified": "true"}
+
+	if err := c.Update(context.TODO(), &configMap); err != nil {
+		t.Fatalf("An error occurred while modifying the config map: (%v)", err)
+	}
+
+	// Testing.
+	isReserved, err := budget.Reserve(startTime.Add(time.Minute))
+
+	assert.NoError(t, err, "Deletions are persisted over the modified config map.")
+	assert.True(t, isReserved, "Deletion is reserved.")
+
+	if err := c.Get(context.TODO(), key, &configMap); err != nil {
+		t.Fatalf("An error occurred while fetching the config map: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		"2010-01-08T12:00:00Z\n2010-01-08T12:01:00Z",
+		configMap.Data[deletionBudgetDeletionTimesKey],
+		"Both deletions are persisted.",
+	)
+
+	isReserved, err = budget.Reserve(startTime.Add(2 * time.Minute))
+
+	assert.NoError(t, err, "Exceeding deletion isn't persisted.")
+	assert.False(t, isReserved, "Deletion over the budget isn't reserved.")
+}
+
+// Case: restart the operator with the deletion budget.
+// Where: two deletions are allowed within an hour, two deletions are recorded before the restart.
+// Expected: deletions are persisted to the config map and the restarted budget is exceeded until the first deletion
+// leaves the hour.
+func TestDeletionBudgetRestart(t *testing.T) {
+	// Set up data for tests.
+	var (
+		c         = fake.NewFakeClientWithScheme(scheme.Scheme)
+		startTime = time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+	)
+
+	newBudget := func() *DeletionBudget {
+		budget := NewDeletionBudget(2, time.Hour)
+		budget.Client = c
+		budget.Namespace = "stale-feature-branch-operator"
+
+		return budget
+	}
+
+	budget := newBudget()
+
+	for _, deletionTime := range []time.Time{startTime, startTime.Add(30 * time.Minute)} {
+		if _, err := budget.Reserve(deletionTime); err != nil {
+			t.Fatalf("An error occurred while recording a deletion: (%v)", err)
+		}
+	}
+
+	// Testing.
+	var configMap corev1.ConfigMap
+
+	err := c.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: "stale-feature-branch-operator", Name: DeletionBudgetConfigMapName},
+		&configMap,
+	)
+
+	assert.NoError(t, err, "Deletions are persisted to the config map.")
+
+	restartedBudget := newBudget()
+
+	if err := restartedBudget.Load(c, startTime.Add(45*time.Minute)); err != nil {
+		t.Fatalf("An error occurred while loading the deletion budget: (%v)", err)
+	}
+
+	assert.True(t, restartedBudget.IsExceeded(startTime.Add(59*time.Minute)), "Restarted budget is exceeded within the hour.")
+	assert.False(
+		t,
+		restartedBudget.IsExceeded(startTime.Add(61*time.Minute)),
+		"Restarted budget is released once the first deletion leaves the hour.",
+	)
+
+	emptyBudget := NewDeletionBudget(2, time.Hour)
+	emptyBudget.Client = fake.NewFakeClientWithScheme(scheme.Scheme)
+	emptyBudget.Namespace = "stale-feature-branch-operator"
+
+	assert.NoError(t, emptyBudget.Load(emptyBudget.Client, startTime), "Budget without the config map is loaded.")
+	assert.False(t, emptyBudget.IsExceeded(startTime), "Budget without the config map isn't exceeded.")
+}
+
+// Case: create the deletion budget from environment variables.
+// Where: max deletions are or aren't set, window minutes are or aren't set.
+// Expected: budget is absent without max deletions, window defaults to an hour, invalid values aren't accepted.
+func TestNewDeletionBudgetFromEnvironment(t *testing.T) {
+	// Set up data for tests.
+	defer os.Unsetenv("MAX_DELETIONS_PER_WINDOW")
+	defer os.Unsetenv("DELETION_WINDOW_MINUTES")
+
+	// Testing.
+	budget, err := NewDeletionBudgetFromEnvironment()
+
+	assert.NoError(t, err, "Budget is optional.")
+	assert.Nil(t, budget, "Deletions are unlimited without max deletions.")
+
+	os.Setenv("MAX_DELETIONS_PER_WINDOW", "20")
+
+	budget, err = NewDeletionBudgetFromEnvironment()
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a deletion budget: (%v)", err)
+	}
+
+	assert.Equal(t, 20, budget.MaxDeletions, "Max deletions are read from the environment.")
+	assert.Equal(t, time.Hour, budget.Window, "Window is an hour by default.")
+
+	os.Setenv("DELETION_WINDOW_MINUTES", "0")
+
+	_, err = NewDeletionBudgetFromEnvironment()
+
+	assert.Error(t, err, "Window should be positive.")
+
+	os.Setenv("MAX_DELETIONS_PER_WINDOW", "many")
+
+	_, err = NewDeletionBudgetFromEnvironment()
+
+	assert.Error(t, err, "Max deletions should be an integer.")
+}
+
+// Case: delete stale feature branches.
+// Where: max deletions per run is less than a number of stale namespaces.
+// Expected: processing stops after max deletions, budget exceeded condition and warning event are recorded.
+func TestReconcilerStaleFeatureBranchesMaxDeletionsPerRun(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace = "stale-feature-branch-operator"
+		recorder                    = record.NewFakeRecorder(100)
+		reconciler                  ReconcileStaleFeatureBranch
+		request                     reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+			MaxDeletionsPerRun: 2,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-2"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-3"}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: recorder,
+		Budget:   NewDeletionBudget(20, time.Hour),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while reconciling a stale feature branch: (%v)", err)
+	}
+
+	var namespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &namespaces); err != nil {
+		t.Fatalf("An error occurred while fetching namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, 1, len(namespaces.Items), "Only max deletions per run are deleted.")
+	assert.Equal(t, "project-pr-3", namespaces.Items[0].Name, "The rest of namespaces aren't deleted.")
+
+	var updatedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &updatedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching a stale feature branch: (%v)", err)
+	}
+
+	budgetExceeded := GetCondition(updatedStaleFeatureBranch.Status, featurebranchv2.StaleFeatureBranchBudgetExceeded)
+
+	assert.Equal(t, corev1.ConditionTrue, budgetExceeded.Status, "Budget exceeded condition is set.")
+	assert.Equal(t, MaxDeletionsPerRunConditionReason, budgetExceeded.Reason, "Max deletions per run is the reason.")
+	assert.Equal(t, BudgetExceededSkipReason, updatedStaleFeatureBranch.Status.SkippedNamespaces[0].Reason, "Namespace is skipped.")
+
+	assert.Contains(
+		t,
+		receiveEvents(recorder),
+		"Warning BudgetExceeded Deletion budget is exceeded, namespace project-pr-3 and the rest aren't processed. "+
+			"Max deletions per run (2) are reached.",
+		"Exceeded budget is recorded to events.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: deletion budget shared by all stale feature branches is exhausted by a previous run.
+// Expected: nothing is deleted, budget exceeded condition is set.
+func TestReconcilerStaleFeatureBranchesDeletionWindow(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace = "stale-feature-branch-operator"
+		budget                      = NewDeletionBudget(1, time.Hour)
+		reconciler                  ReconcileStaleFeatureBranch
+		request                     reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	budget.Reserve(time.Now())
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+		Budget:   budget,
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while reconciling a stale feature branch: (%v)", err)
+	}
+
+	var namespace corev1.Namespace
+
+	assert.NoError(
+		t,
+		reconciler.Client.Get(context.TODO(), types.NamespacedName{Name: "project-pr-1"}, &namespace),
+		"Namespace isn't deleted.",
+	)
+
+	var updatedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &updatedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching a stale feature branch: (%v)", err)
+	}
+
+	budgetExceeded := GetCondition(updatedStaleFeatureBranch.Status, featurebranchv2.StaleFeatureBranchBudgetExceeded)
+
+	assert.Equal(t, corev1.ConditionTrue, budgetExceeded.Status, "Budget exceeded condition is set.")
+	assert.Equal(t, DeletionWindowConditionReason, budgetExceeded.Reason, "Shared deletion budget is the reason.")
+}
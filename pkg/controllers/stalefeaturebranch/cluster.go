@@ -0,0 +1,99 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ reconcile.Reconciler = &ReconcileClusterStaleFeatureBranch{}
+
+// ReconcileClusterStaleFeatureBranch processes feature branches' namespaces by cluster stale feature branches the same
+// way stale feature branches do, but regardless of the namespaces' owners.
+type ReconcileClusterStaleFeatureBranch struct {
+	*ReconcileStaleFeatureBranch
+}
+
+func (r *ReconcileClusterStaleFeatureBranch) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	var clusterStaleFeatureBranch featurebranchv2.ClusterStaleFeatureBranch
+
+	if err := r.Client.Get(context.TODO(), request.NamespacedName, &clusterStaleFeatureBranch); err != nil {
+		if errors.IsNotFound(err) {
+			ForgetMetrics(request.NamespacedName)
+		}
+
+		logger.Error(err, "Unable to fetch a cluster stale feature branch.")
+		return reconcile.Result{}, nil
+	}
+
+	staleFeatureBranch := NewClusterScopedStaleFeatureBranch(clusterStaleFeatureBranch)
+
+	return r.reconcileStaleFeatureBranch(&staleFeatureBranch, func() error {
+		clusterStaleFeatureBranch.Status = staleFeatureBranch.Status
+		return r.Client.Status().Update(context.TODO(), &clusterStaleFeatureBranch)
+	})
+}
+
+// NewClusterScopedStaleFeatureBranch creates the stale feature branch without a namespace from the cluster stale
+// feature branch, so it's processed the same way.
+func NewClusterScopedStaleFeatureBranch(clusterStaleFeatureBranch featurebranchv2.ClusterStaleFeatureBranch) featurebranchv2.StaleFeatureBranch {
+	return featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: clusterStaleFeatureBranch.ObjectMeta,
+		Spec:       clusterStaleFeatureBranch.Spec,
+		Status:     clusterStaleFeatureBranch.Status,
+	}
+}
+
+// IsClusterScoped reports whether the stale feature branch is created from a cluster stale feature branch.
+func IsClusterScoped(staleFeatureBranch featurebranchv2.StaleFeatureBranch) bool {
+	return staleFeatureBranch.Namespace == ""
+}
+
+// DescribeStaleFeatureBranch returns the stale feature branch's kind and name for messages.
+func DescribeStaleFeatureBranch(staleFeatureBranch featurebranchv2.StaleFeatureBranch) string {
+	if IsClusterScoped(staleFeatureBranch) {
+		return fmt.Sprintf("cluster stale feature branch %s", staleFeatureBranch.Name)
+	}
+
+	return fmt.Sprintf("stale feature branch %s/%s", staleFeatureBranch.Namespace, staleFeatureBranch.Name)
+}
+
+// getEventObject returns the object to record the stale feature branch's events to, so events of cluster stale
+// feature branches refer to them instead of stale feature branches.
+func getEventObject(staleFeatureBranch *featurebranchv2.StaleFeatureBranch) runtime.Object {
+	if IsClusterScoped(*staleFeatureBranch) {
+		return &featurebranchv2.ClusterStaleFeatureBranch{ObjectMeta: staleFeatureBranch.ObjectMeta}
+	}
+
+	return staleFeatureBranch
+}
+
+// getCredentialsSecret fetches the secret with credentials from the stale feature branch's namespace. Credentials of
+// cluster stale feature branches are read from the operator's namespace. Secret is read from the API server, so the
+// informer cache doesn't keep all secrets of the cluster.
+func (r *ReconcileStaleFeatureBranch) getCredentialsSecret(staleFeatureBranch featurebranchv2.StaleFeatureBranch, name string) (corev1.Secret, error) {
+	var credentials corev1.Secret
+
+	credentialsNamespace := staleFeatureBranch.Namespace
+
+	if IsClusterScoped(staleFeatureBranch) {
+		credentialsNamespace = os.Getenv("OPERATOR_NAMESPACE")
+	}
+
+	credentialsName := types.NamespacedName{
+		Namespace: credentialsNamespace,
+		Name:      name,
+	}
+
+	err := r.getAPIReader().Get(context.TODO(), credentialsName, &credentials)
+
+	return credentials, err
+}
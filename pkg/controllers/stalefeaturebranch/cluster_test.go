@@ -0,0 +1,252 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Case: delete stale feature branches by a namespaced stale feature branch.
+// Where: namespaces are owned by the stale feature branch's namespace, by another namespace or by none.
+// Expected: only namespaces owned by the stale feature branch's namespace are deleted.
+func TestReconcilerStaleFeatureBranchesOwner(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch"
+		staleFeatureBranchNamespace = "team-a"
+		reconciler                  ReconcileStaleFeatureBranch
+		request                     reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a-pr-1",
+			Labels: map[string]string{featurebranch.OwnerLabel: "team-a"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-b-pr-1",
+			Labels: map[string]string{featurebranch.OwnerLabel: "team-b"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "unowned-pr-1",
+		}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, objects...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while reconciling a stale feature branch: (%v)", err)
+	}
+
+	var namespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &namespaces); err != nil {
+		t.Fatalf("An error occurred while fetching namespaces: (%v)", err)
+	}
+
+	var namespacesNames []string
+
+	for _, namespace := range namespaces.Items {
+		namespacesNames = append(namespacesNames, namespace.Name)
+	}
+
+	assert.Equal(
+		t,
+		[]string{"team-b-pr-1", "unowned-pr-1"},
+		namespacesNames,
+		"Namespaces not owned by the stale feature branch's namespace aren't deleted.",
+	)
+}
+
+// Case: delete stale feature branches by a cluster stale feature branch.
+// Where: namespaces are owned by different namespaces or by none.
+// Expected: all stale namespaces are deleted, status and events are recorded to the cluster stale feature branch.
+func TestReconcilerClusterStaleFeatureBranches(t *testing.T) {
+	// Set up data for tests.
+	var (
+		clusterStaleFeatureBranchName = "cluster-stale-feature-branch"
+		recorder                      = record.NewFakeRecorder(100)
+		reconciler                    ReconcileClusterStaleFeatureBranch
+		request                       reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterStaleFeatureBranchName,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+		},
+	}
+
+	objects := []runtime.Object{
+		clusterStaleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a-pr-1",
+			Labels: map[string]string{featurebranch.OwnerLabel: "team-a"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name: "unowned-pr-1",
+		}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, clusterStaleFeatureBranch)
+
+	reconciler = ReconcileClusterStaleFeatureBranch{
+		ReconcileStaleFeatureBranch: &ReconcileStaleFeatureBranch{
+			Client:   fake.NewFakeClientWithScheme(s, objects...),
+			Scheme:   s,
+			Recorder: recorder,
+		},
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name: clusterStaleFeatureBranchName,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while reconciling a cluster stale feature branch: (%v)", err)
+	}
+
+	var updatedClusterStaleFeatureBranch featurebranchv2.ClusterStaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &updatedClusterStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching a cluster stale feature branch: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		[]string{"team-a-pr-1", "unowned-pr-1"},
+		updatedClusterStaleFeatureBranch.Status.DeletedNamespaces,
+		"Namespaces are deleted regardless of their owners.",
+	)
+
+	assert.Contains(
+		t,
+		receiveEvents(recorder),
+		"Normal Deleting Namespace unowned-pr-1 is being deleted by cluster stale feature branch cluster-stale-feature-branch.",
+		"Cluster stale feature branch is referred by events.",
+	)
+}
+
+// Case: get secrets with credentials of stale feature branches.
+// Where: secrets are only known to the API server, the cached client doesn't have them.
+// Expected: credentials of the namespaced stale feature branch are read from its namespace, credentials of the cluster
+// stale feature branch are read from the operator's namespace.
+func TestGetCredentialsSecretAPIReader(t *testing.T) {
+	// Set up data for tests.
+	if err := os.Setenv("OPERATOR_NAMESPACE", "stale-feature-branch-operator"); err != nil {
+		t.Fatalf("An error occurred while setting the operator namespace: (%v)", err)
+	}
+
+	defer os.Unsetenv("OPERATOR_NAMESPACE")
+
+	secret := func(namespace string, token string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "credentials", Namespace: namespace},
+			Data:       map[string][]byte{"token": []byte(token)},
+		}
+	}
+
+	reconciler := ReconcileStaleFeatureBranch{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		APIReader: fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			secret("team-a", "team-a-token"),
+			secret("stale-feature-branch-operator", "operator-token"),
+		),
+		Scheme: scheme.Scheme,
+	}
+
+	cases := []struct {
+		staleFeatureBranch featurebranchv2.StaleFeatureBranch
+		expectedToken      string
+		description        string
+	}{
+		{
+			featurebranchv2.StaleFeatureBranch{ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "team-a"}},
+			"team-a-token",
+			"Credentials of the namespaced stale feature branch are read from its namespace.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranch{ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch"}},
+			"operator-token",
+			"Credentials of the cluster stale feature branch are read from the operator's namespace.",
+		},
+	}
+
+	// Testing.
+	for _, testCase := range cases {
+		credentials, err := reconciler.getCredentialsSecret(testCase.staleFeatureBranch, "credentials")
+
+		if err != nil {
+			t.Fatalf("An error occurred while getting the credentials secret: (%v)", err)
+		}
+
+		assert.Equal(t, testCase.expectedToken, string(credentials.Data["token"]), testCase.description)
+	}
+}
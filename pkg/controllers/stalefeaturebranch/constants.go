@@ -1,6 +1,130 @@
 package stalefeaturebranch
 
+import "time"
+
+const (
+	debugIsEnabled  = "true"
+	dryRunIsEnabled = "true"
+)
+
+const (
+	metricsNamespace = "stale_feature_branch"
+)
+
+const (
+	DeletingEventReason           = "Deleting"
+	DeletedEventReason            = "Deleted"
+	DeleteFailedEventReason       = "DeleteFailed"
+	DryRunEventReason             = "DryRun"
+	MarkedForDeletionEventReason  = "MarkedForDeletion"
+	UnmarkedEventReason           = "Unmarked"
+	RescuedEventReason            = "Rescued"
+	HibernatedEventReason         = "Hibernated"
+	WokenEventReason              = "Woken"
+	BackedUpEventReason           = "BackedUp"
+	BackupFailedEventReason       = "BackupFailed"
+	BudgetExceededEventReason     = "BudgetExceeded"
+	StuckTerminatingEventReason   = "StuckTerminating"
+	FinalizersClearedEventReason  = "FinalizersCleared"
+	NotificationFailedEventReason = "NotificationFailed"
+)
+
+const (
+	ProtectedSkipReason           = "Protected"
+	ExcludedSkipReason            = "Excluded"
+	KeepAnnotationSkipReason      = "KeepAnnotation"
+	KeepUntilAnnotationSkipReason = "KeepUntilAnnotation"
+	NotStaleSkipReason            = "NotStale"
+	DryRunSkipReason              = "DryRun"
+	MarkedForDeletionSkipReason   = "MarkedForDeletion"
+	HibernatedSkipReason          = "Hibernated"
+	BudgetExceededSkipReason      = "BudgetExceeded"
+	TerminatingSkipReason         = "Terminating"
+	ExpiresAtAnnotationSkipReason = "ExpiresAtAnnotation"
+	PullRequestOpenSkipReason     = "PullRequestOpen"
+	PullRequestUnknownSkipReason  = "PullRequestUnknown"
+)
+
+const (
+	keepAnnotationIsEnabled     = "true"
+	keepUntilDateLayout         = "2006-01-02"
+	markedForDeletionLabelValue = "true"
+	wakeAnnotationIsEnabled     = "true"
+)
+
+const (
+	namespaceURLPlaceholder            = "{namespace}"
+	defaultHTTPStalenessTimeoutSeconds = 10
+)
+
+const (
+	runTriggersBufferSize = 100
+)
+
+const (
+	// statusNamespacesLimit bounds matched and skipped namespaces listed in the status, so it doesn't grow with a
+	// cluster, the rest of them are only counted
+	statusNamespacesLimit = 50
+)
+
+const (
+	pullRequestStateCacheMaxAge = 5 * time.Minute
+)
+
+const (
+	// NamespaceOwnerIndexField is the informer cache's index of namespaces by their owners' labels
+	NamespaceOwnerIndexField = "metadata.labels.owner"
+	namespaceListPageSize    = 500
+	namespaceBatchMaxAge     = 10 * time.Second
+)
+
+const (
+	failedNamespaceRetryBaseDelay = time.Minute
+	failedNamespaceRetryMaxDelay  = time.Hour
+)
+
+const (
+	// DefaultDeletionWindowMinutes is used if DELETION_WINDOW_MINUTES environment variable isn't set
+	DefaultDeletionWindowMinutes = 60
+	// DeletionBudgetConfigMapName is a config map in the operator's namespace the deletion budget's deletions are
+	// persisted to
+	DeletionBudgetConfigMapName    = "stale-feature-branch-operator-deletion-budget"
+	deletionBudgetDeletionTimesKey = "deletionTimes"
+)
+
+const (
+	backupTimeLayout           = "20060102-150405"
+	s3TimeoutSeconds           = 60
+	s3AccessKeyIDSecretKey     = "accessKeyId"
+	s3SecretAccessKeySecretKey = "secretAccessKey"
+)
+
+const (
+	gitProviderTimeoutSeconds    = 10
+	gitProviderTokenSecretKey    = "token"
+	gitProviderUsernameSecretKey = "username"
+)
+
+const (
+	notificationTimeoutSeconds    = 10
+	notificationURLSecretKey      = "url"
+	notificationUsernameSecretKey = "username"
+	notificationPasswordSecretKey = "password"
+)
+
+// ProtectedNamespaces are system namespaces that are never deleted
+var ProtectedNamespaces = []string{
+	"default",
+	"kube-system",
+	"kube-public",
+	"kube-node-lease",
+}
+
 const (
-	debugIsEnabled     = "true"
-	HoursInDay     int = 24
+	ProcessingSucceededConditionReason   = "ProcessingSucceeded"
+	ProcessingFailedConditionReason      = "ProcessingFailed"
+	InvalidSpecificationsConditionReason = "InvalidSpecifications"
+	WithinBudgetConditionReason          = "WithinBudget"
+	MaxDeletionsPerRunConditionReason    = "MaxDeletionsPerRun"
+	DeletionWindowConditionReason        = "DeletionWindow"
 )
@@ -1,8 +1,9 @@
 package stalefeaturebranch
 
 import (
-	featurebranchv1 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v1"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
 
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -13,7 +14,7 @@ import (
 
 var logger = logf.Log.WithName("stale-feature-branch-controller")
 
-func CreateController(mgr manager.Manager, r reconcile.Reconciler) error {
+func CreateController(mgr manager.Manager, r reconcile.Reconciler, triggers *RunTriggers, batch *NamespaceBatch) error {
 
 	c, err := controller.New("stalefeaturebranch-controller", mgr, controller.Options{Reconciler: r})
 
@@ -21,7 +22,69 @@ func CreateController(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	err = c.Watch(&source.Kind{Type: &featurebranchv1.StaleFeatureBranch{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &featurebranchv2.StaleFeatureBranch{}}, &handler.EnqueueRequestForObject{})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Channel{Source: triggers.StaleFeatureBranchEvents}, &handler.EnqueueRequestForObject{})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Namespace{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &NamespaceMapper{
+				Client:          mgr.GetClient(),
+				Triggers:        triggers,
+				Batch:           batch,
+				IsClusterScoped: false,
+			},
+		},
+		NewNamespaceChangedPredicate(),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func CreateClusterController(mgr manager.Manager, r reconcile.Reconciler, triggers *RunTriggers, batch *NamespaceBatch) error {
+	c, err := controller.New("clusterstalefeaturebranch-controller", mgr, controller.Options{Reconciler: r})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Kind{Type: &featurebranchv2.ClusterStaleFeatureBranch{}}, &handler.EnqueueRequestForObject{})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(&source.Channel{Source: triggers.ClusterStaleFeatureBranchEvents}, &handler.EnqueueRequestForObject{})
+
+	if err != nil {
+		return err
+	}
+
+	err = c.Watch(
+		&source.Kind{Type: &corev1.Namespace{}},
+		&handler.EnqueueRequestsFromMapFunc{
+			ToRequests: &NamespaceMapper{
+				Client:          mgr.GetClient(),
+				Triggers:        triggers,
+				Batch:           batch,
+				IsClusterScoped: true,
+			},
+		},
+		NewNamespaceChangedPredicate(),
+	)
 
 	if err != nil {
 		return err
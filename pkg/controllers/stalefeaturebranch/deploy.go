@@ -0,0 +1,152 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetNamespaceLastActivity returns the namespace's last activity detected by the stale feature branch's staleness
+// policy. If the namespace is annotated with its last deploy time by continuous integration, the annotation is used
+// instead of the policy. Rescue of the namespace from deletion and wake of the hibernated namespace count as activities
+// regardless of the policy.
+func (r *ReconcileStaleFeatureBranch) GetNamespaceLastActivity(spec featurebranchv2.StaleFeatureBranchSpec, namespace corev1.Namespace) (LastActivity, error) {
+	lastActivity, isDeployAnnotated, err := getAnnotatedLastDeploy(spec, namespace)
+
+	if err != nil {
+		return LastActivity{}, err
+	}
+
+	if !isDeployAnnotated {
+		policy, err := NewStalenessPolicy(spec)
+
+		if err != nil {
+			return LastActivity{}, err
+		}
+
+		contents, err := r.ListNamespaceContents(namespace)
+
+		if err != nil {
+			return LastActivity{}, err
+		}
+
+		lastActivity, err = policy.GetLastActivity(namespace, contents)
+
+		if err != nil {
+			return LastActivity{}, err
+		}
+	}
+
+	annotationReasons := map[string]string{
+		featurebranch.RescuedAtAnnotation: "rescue",
+		featurebranch.WokenAtAnnotation:   "wake",
+	}
+
+	for _, annotation := range []string{featurebranch.RescuedAtAnnotation, featurebranch.WokenAtAnnotation} {
+		if annotationTime, err := time.Parse(time.RFC3339, namespace.Annotations[annotation]); err == nil {
+			lastActivity = getLatestActivity(lastActivity, LastActivity{Time: annotationTime, Reason: annotationReasons[annotation]})
+		}
+	}
+
+	return lastActivity, nil
+}
+
+// GetNamespaceExpiration returns the time the namespace is stale at by its expires at annotation regardless of the stale
+// feature branch's policy and whether the namespace is annotated.
+func GetNamespaceExpiration(namespace corev1.Namespace) (time.Time, bool, error) {
+	expiresAt, isExpirable := namespace.Annotations[featurebranch.ExpiresAtAnnotation]
+
+	if !isExpirable {
+		return time.Time{}, false, nil
+	}
+
+	expirationTime, err := time.Parse(time.RFC3339, expiresAt)
+
+	if err != nil {
+		return time.Time{}, true, fmt.Errorf("expected RFC 3339 time, got %q", expiresAt)
+	}
+
+	return expirationTime, true, nil
+}
+
+// ListNamespaceContents lists the namespace's workloads, their revisions and pods from the API server, so the informer
+// cache doesn't start cluster-wide informers of pods and replica sets for each processed namespace.
+func (r *ReconcileStaleFeatureBranch) ListNamespaceContents(namespace corev1.Namespace) (NamespaceContents, error) {
+	inNamespace := client.InNamespace(namespace.Name)
+
+	var deployments appsv1.DeploymentList
+
+	if err := r.getAPIReader().List(context.TODO(), &deployments, inNamespace); err != nil {
+		return NamespaceContents{}, err
+	}
+
+	var statefulSets appsv1.StatefulSetList
+
+	if err := r.getAPIReader().List(context.TODO(), &statefulSets, inNamespace); err != nil {
+		return NamespaceContents{}, err
+	}
+
+	var daemonSets appsv1.DaemonSetList
+
+	if err := r.getAPIReader().List(context.TODO(), &daemonSets, inNamespace); err != nil {
+		return NamespaceContents{}, err
+	}
+
+	var controllerRevisions appsv1.ControllerRevisionList
+
+	if err := r.getAPIReader().List(context.TODO(), &controllerRevisions, inNamespace); err != nil {
+		return NamespaceContents{}, err
+	}
+
+	var replicaSets appsv1.ReplicaSetList
+
+	if err := r.getAPIReader().List(context.TODO(), &replicaSets, inNamespace); err != nil {
+		return NamespaceContents{}, err
+	}
+
+	var pods corev1.PodList
+
+	if err := r.getAPIReader().List(context.TODO(), &pods, inNamespace); err != nil {
+		return NamespaceContents{}, err
+	}
+
+	return NamespaceContents{
+		Deployments:         deployments.Items,
+		StatefulSets:        statefulSets.Items,
+		DaemonSets:          daemonSets.Items,
+		ControllerRevisions: controllerRevisions.Items,
+		ReplicaSets:         replicaSets.Items,
+		Pods:                pods.Items,
+	}, nil
+}
+
+// getAnnotatedLastDeploy returns the namespace's last deploy stamped by continuous integration to the stale feature
+// branch's last deployed at annotation and whether the namespace is annotated.
+func getAnnotatedLastDeploy(spec featurebranchv2.StaleFeatureBranchSpec, namespace corev1.Namespace) (LastActivity, bool, error) {
+	annotation := spec.LastDeployedAtAnnotation
+
+	if annotation == "" {
+		annotation = featurebranch.LastDeployedAtAnnotation
+	}
+
+	lastDeployedAt, isAnnotated := namespace.Annotations[annotation]
+
+	if !isAnnotated {
+		return LastActivity{}, false, nil
+	}
+
+	lastDeployTime, err := time.Parse(time.RFC3339, lastDeployedAt)
+
+	if err != nil {
+		return LastActivity{}, true, fmt.Errorf("invalid time of annotation %s: %v", annotation, err)
+	}
+
+	return LastActivity{Time: lastDeployTime, Reason: "deploy"}, true, nil
+}
@@ -0,0 +1,314 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Case: list namespace's contents.
+// Where: namespace contains a replica set, a controller revision and a pod, another namespace contains a pod, they are
+// only known to the API server, the cached client doesn't have them.
+// Expected: only resources of the namespace are listed from the API server.
+func TestListNamespaceContents(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceName = "project-pr-1"
+		reconciler    ReconcileStaleFeatureBranch
+	)
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaceName,
+		},
+	}
+
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "project-pr-1-848d5fdff6",
+			Namespace: namespaceName,
+		},
+	}
+
+	controllerRevision := &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "project-pr-1-database-5d8f7b9c4",
+			Namespace: namespaceName,
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "project-pr-1-848d5fdff6-rpmzw",
+			Namespace: namespaceName,
+		},
+	}
+
+	podInAnotherNamespace := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "project-pr-2-848d5fdff6-m7sch",
+			Namespace: "project-pr-2",
+		},
+	}
+
+	objects := []runtime.Object{
+		namespace,
+		replicaSet,
+		controllerRevision,
+		pod,
+		podInAnotherNamespace,
+	}
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:    fake.NewFakeClientWithScheme(scheme.Scheme),
+		APIReader: fake.NewFakeClientWithScheme(scheme.Scheme, objects...),
+		Scheme:    scheme.Scheme,
+	}
+
+	// Testing.
+	contents, err := reconciler.ListNamespaceContents(*namespace)
+
+	if err != nil {
+		t.Fatalf("An error occurred while listing the namespace's contents: (%v)", err)
+	}
+
+	assert.Len(t, contents.ReplicaSets, 1, "Replica set of the namespace is listed.")
+	assert.Len(t, contents.ControllerRevisions, 1, "Controller revision of the namespace is listed.")
+
+	if assert.Len(t, contents.Pods, 1, "Only the pod of the namespace is listed.") {
+		assert.Equal(t, pod.Name, contents.Pods[0].Name, "Pod of another namespace isn't listed.")
+	}
+}
+
+// Case: get namespace's last activity.
+// Where: namespaces are annotated with their last deploy time by continuous integration after their pods' starts, one
+// of them is annotated with the default annotation and another one with the stale feature branch's annotation, the
+// third one isn't annotated.
+// Expected: annotation's time replaces the staleness strategies regardless of their combination, the strategies are
+// used for the namespace without the annotation.
+func TestGetNamespaceLastActivityDeployAnnotation(t *testing.T) {
+	// Set up data for tests.
+	var (
+		customAnnotation           = "ci.example.com/deployed-at"
+		lastDeployedAt             = time.Date(2010, time.January, 5, 0, 0, 0, 0, time.UTC)
+		podStartTimestamp          = metav1.Date(2010, time.January, 4, 0, 0, 0, 0, time.UTC)
+		namespaceCreationTimestamp = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		reconciler                 ReconcileStaleFeatureBranch
+	)
+
+	namespace := func(name string, annotation string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: namespaceCreationTimestamp,
+				Annotations:       map[string]string{annotation: lastDeployedAt.Format(time.RFC3339)},
+			},
+		}
+	}
+
+	pod := func(namespaceName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      namespaceName + "-848d5fdff6-rpmzw",
+				Namespace: namespaceName,
+			},
+			Status: corev1.PodStatus{
+				StartTime: &podStartTimestamp,
+			},
+		}
+	}
+
+	defaultAnnotatedNamespace := namespace("project-pr-1", featurebranch.LastDeployedAtAnnotation)
+	customAnnotatedNamespace := namespace("project-pr-2", customAnnotation)
+	notAnnotatedNamespace := namespace("project-pr-3", "")
+	notAnnotatedNamespace.Annotations = nil
+
+	objects := []runtime.Object{
+		defaultAnnotatedNamespace,
+		customAnnotatedNamespace,
+		notAnnotatedNamespace,
+		pod(defaultAnnotatedNamespace.Name),
+		pod(customAnnotatedNamespace.Name),
+		pod(notAnnotatedNamespace.Name),
+	}
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme, objects...),
+		Scheme: scheme.Scheme,
+	}
+
+	podStartStaleness := func(combination featurebranchv2.StalenessCombinationType) *featurebranchv2.StalenessSpec {
+		return &featurebranchv2.StalenessSpec{
+			Strategies:  []featurebranchv2.StalenessStrategySpec{{Type: featurebranchv2.LastPodStartStalenessStrategyType}},
+			Combination: combination,
+		}
+	}
+
+	var (
+		deployActivity   = LastActivity{Time: lastDeployedAt, Reason: "deploy"}
+		podStartActivity = func(namespace *corev1.Namespace) LastActivity {
+			return LastActivity{Time: podStartTimestamp.Time, Reason: fmt.Sprintf("start of pod %s-848d5fdff6-rpmzw", namespace.Name)}
+		}
+	)
+
+	cases := []struct {
+		spec                 featurebranchv2.StaleFeatureBranchSpec
+		namespace            *corev1.Namespace
+		expectedLastActivity LastActivity
+		description          string
+	}{
+		{
+			featurebranchv2.StaleFeatureBranchSpec{},
+			defaultAnnotatedNamespace,
+			deployActivity,
+			"Default annotation's time is the last activity.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{LastDeployedAtAnnotation: customAnnotation},
+			customAnnotatedNamespace,
+			deployActivity,
+			"Stale feature branch's annotation's time is the last activity.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				LastDeployedAtAnnotation: customAnnotation,
+				Staleness:                podStartStaleness(featurebranchv2.AllStalenessCombinationType),
+			},
+			customAnnotatedNamespace,
+			deployActivity,
+			"Stale feature branch's annotation replaces the specified strategies combined by All.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				LastDeployedAtAnnotation: customAnnotation,
+				Staleness:                podStartStaleness(featurebranchv2.AnyStalenessCombinationType),
+			},
+			customAnnotatedNamespace,
+			deployActivity,
+			"Stale feature branch's annotation replaces the specified strategies combined by Any.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{Staleness: podStartStaleness(featurebranchv2.AllStalenessCombinationType)},
+			defaultAnnotatedNamespace,
+			deployActivity,
+			"Default annotation replaces the specified strategies.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{},
+			notAnnotatedNamespace,
+			podStartActivity(notAnnotatedNamespace),
+			"Strategies are used for the namespace without the annotation.",
+		},
+	}
+
+	// Testing.
+	for _, testCase := range cases {
+		lastActivity, err := reconciler.GetNamespaceLastActivity(testCase.spec, *testCase.namespace)
+
+		if err != nil {
+			t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+		}
+
+		assert.Equal(t, testCase.expectedLastActivity.Reason, lastActivity.Reason, testCase.description)
+		assert.True(t, testCase.expectedLastActivity.Time.Equal(lastActivity.Time), testCase.description)
+	}
+}
+
+// Case: decide whether namespace is to be deleted.
+// Where: namespaces are created just now, but annotated to expire an hour ago, in an hour or at an invalid time.
+// Expected: expired namespace is deleted regardless of the stale feature branch's threshold, others are skipped.
+func TestIsNamespaceToBeDeletedExpiresAt(t *testing.T) {
+	// Set up data for tests.
+	var (
+		currentTimestamp = time.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+		reconciler       ReconcileStaleFeatureBranch
+	)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	staleFeatureBranch := featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 72 * time.Hour},
+		},
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		Scheme: scheme.Scheme,
+	}
+
+	namespace := func(name string, expiresAt string) corev1.Namespace {
+		return corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.Time{Time: currentTimestamp},
+				Annotations:       map[string]string{featurebranch.ExpiresAtAnnotation: expiresAt},
+			},
+		}
+	}
+
+	cases := []struct {
+		namespace        corev1.Namespace
+		expectedDecision NamespaceDecision
+		description      string
+	}{
+		{
+			namespace("project-pr-1", "2010-01-10T11:00:00Z"),
+			NamespaceDecision{IsToBeDeleted: true},
+			"Namespace expired an hour ago is deleted.",
+		},
+		{
+			namespace("project-pr-2", "2010-01-10T13:00:00Z"),
+			NamespaceDecision{
+				SkipReason:  ExpiresAtAnnotationSkipReason,
+				SkipMessage: "Namespace expires at 2010-01-10T13:00:00Z.",
+				StaleTime:   time.Date(2010, time.January, 10, 13, 0, 0, 0, time.UTC),
+			},
+			"Namespace expiring in an hour is skipped.",
+		},
+		{
+			namespace("project-pr-3", "tomorrow"),
+			NamespaceDecision{
+				SkipReason: ExpiresAtAnnotationSkipReason,
+				SkipMessage: "Namespace is annotated with feature-branch.dmytrostriletskyi.com/expires-at, but its value is " +
+					"invalid: expected RFC 3339 time, got \"tomorrow\".",
+			},
+			"Namespace expiring at an invalid time is skipped.",
+		},
+	}
+
+	// Testing.
+	for _, testCase := range cases {
+		decision, err := reconciler.IsNamespaceToBeDeleted(staleFeatureBranch, matcher, testCase.namespace)
+
+		if err != nil {
+			t.Fatalf("An error occurred while deciding whether the namespace is to be deleted: (%v)", err)
+		}
+
+		assert.Equal(t, testCase.expectedDecision, decision, testCase.description)
+	}
+}
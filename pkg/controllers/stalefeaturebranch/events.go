@@ -0,0 +1,28 @@
+package stalefeaturebranch
+
+import (
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recordEvent records the event to the stale feature branch, so `kubectl describe sfb` tells the story of its runs.
+func (r *ReconcileStaleFeatureBranch) recordEvent(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, eventType string, reason string, messageFmt string, args ...interface{}) {
+	r.Recorder.Eventf(getEventObject(staleFeatureBranch), eventType, reason, messageFmt, args...)
+}
+
+// recordNamespaceEvent records the event both to the stale feature branch and to the namespace, so developers see it
+// by describing their namespaces.
+func (r *ReconcileStaleFeatureBranch) recordNamespaceEvent(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, namespace *corev1.Namespace, eventType string, reason string, messageFmt string, args ...interface{}) {
+	r.Recorder.Eventf(getEventObject(staleFeatureBranch), eventType, reason, messageFmt, args...)
+	r.Recorder.Eventf(namespace, eventType, reason, messageFmt, args...)
+}
+
+// formatEventMessage formats the optional message to be appended to an event's message.
+func formatEventMessage(message string) string {
+	if message == "" {
+		return ""
+	}
+
+	return " " + message
+}
@@ -0,0 +1,89 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetNamespaceMarkTime returns the time the namespace is marked for deletion at and whether it's marked. Namespace
+// with an invalid mark isn't considered marked.
+func GetNamespaceMarkTime(namespace corev1.Namespace) (time.Time, bool) {
+	markedAt, isMarked := namespace.Annotations[featurebranch.MarkedForDeletionAtAnnotation]
+
+	if !isMarked {
+		return time.Time{}, false
+	}
+
+	markTime, err := time.Parse(time.RFC3339, markedAt)
+
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return markTime, true
+}
+
+// IsNamespaceMarked reports whether the namespace has the mark for deletion, even an invalid or a partially removed one.
+func IsNamespaceMarked(namespace corev1.Namespace) bool {
+	_, isAnnotated := namespace.Annotations[featurebranch.MarkedForDeletionAtAnnotation]
+	_, isLabeled := namespace.Labels[featurebranch.MarkedForDeletionLabel]
+
+	return isAnnotated || isLabeled
+}
+
+// IsNamespaceRescued reports whether the namespace's mark for deletion annotation is removed by a developer while
+// the mark for deletion label is still in place.
+func IsNamespaceRescued(namespace corev1.Namespace) bool {
+	_, isAnnotated := namespace.Annotations[featurebranch.MarkedForDeletionAtAnnotation]
+	_, isLabeled := namespace.Labels[featurebranch.MarkedForDeletionLabel]
+
+	return isLabeled && !isAnnotated
+}
+
+// MarkNamespace annotates and labels the namespace as marked for deletion at the time.
+func (r *ReconcileStaleFeatureBranch) MarkNamespace(namespace *corev1.Namespace, markTime time.Time) error {
+	original := namespace.DeepCopy()
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+
+	if namespace.Labels == nil {
+		namespace.Labels = make(map[string]string)
+	}
+
+	namespace.Annotations[featurebranch.MarkedForDeletionAtAnnotation] = markTime.UTC().Format(time.RFC3339)
+	namespace.Labels[featurebranch.MarkedForDeletionLabel] = markedForDeletionLabelValue
+
+	return r.Client.Patch(context.TODO(), namespace, client.MergeFrom(original))
+}
+
+// UnmarkNamespace removes the namespace's mark for deletion.
+func (r *ReconcileStaleFeatureBranch) UnmarkNamespace(namespace *corev1.Namespace) error {
+	original := namespace.DeepCopy()
+
+	delete(namespace.Annotations, featurebranch.MarkedForDeletionAtAnnotation)
+	delete(namespace.Labels, featurebranch.MarkedForDeletionLabel)
+
+	return r.Client.Patch(context.TODO(), namespace, client.MergeFrom(original))
+}
+
+// RescueNamespace removes the rest of the namespace's mark for deletion and annotates the namespace as rescued at the
+// time, so the rescue counts as a deploy.
+func (r *ReconcileStaleFeatureBranch) RescueNamespace(namespace *corev1.Namespace, rescueTime time.Time) error {
+	original := namespace.DeepCopy()
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string]string)
+	}
+
+	namespace.Annotations[featurebranch.RescuedAtAnnotation] = rescueTime.UTC().Format(time.RFC3339)
+	delete(namespace.Labels, featurebranch.MarkedForDeletionLabel)
+
+	return r.Client.Patch(context.TODO(), namespace, client.MergeFrom(original))
+}
@@ -0,0 +1,297 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ValidateAction validates the stale feature branch's action and its thresholds. Hibernated namespaces are deleted only
+// after a longer threshold than the hibernation's one, the grace period and the stuck terminating threshold shouldn't be
+// negative.
+func ValidateAction(spec featurebranchv2.StaleFeatureBranchSpec) error {
+	if spec.StaleAfter.Duration <= 0 {
+		return fmt.Errorf("stale after should be positive, got %s", spec.StaleAfter.Duration)
+	}
+
+	if spec.GracePeriod.Duration < 0 {
+		return fmt.Errorf("grace period should not be negative, got %s", spec.GracePeriod.Duration)
+	}
+
+	if spec.StuckTerminatingAfter.Duration < 0 {
+		return fmt.Errorf("stuck terminating after should not be negative, got %s", spec.StuckTerminatingAfter.Duration)
+	}
+
+	switch spec.Action {
+	case featurebranchv2.DeleteActionType, "":
+		if spec.DeleteAfter.Duration != 0 {
+			return fmt.Errorf("delete after is only supported by %s action", featurebranchv2.HibernateActionType)
+		}
+	case featurebranchv2.HibernateActionType:
+		if spec.DeleteAfter.Duration != 0 && spec.DeleteAfter.Duration <= spec.StaleAfter.Duration {
+			return fmt.Errorf(
+				"delete after (%s) should be greater than stale after (%s)",
+				spec.DeleteAfter.Duration,
+				spec.StaleAfter.Duration,
+			)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", spec.Action)
+	}
+
+	return nil
+}
+
+// IsNamespaceHibernated reports whether the namespace is hibernated.
+func IsNamespaceHibernated(namespace corev1.Namespace) bool {
+	_, isHibernated := namespace.Annotations[featurebranch.HibernatedAtAnnotation]
+	return isHibernated
+}
+
+// IsNamespaceWakeRequested reports whether the namespace is annotated to be woken.
+func IsNamespaceWakeRequested(namespace corev1.Namespace) bool {
+	return namespace.Annotations[featurebranch.WakeAnnotation] == wakeAnnotationIsEnabled
+}
+
+// IsHibernatedNamespaceToBeDeleted reports whether the hibernated namespace is without activity for the second, longer
+// threshold. Hibernated namespaces are never deleted if the threshold isn't specified.
+func (r *ReconcileStaleFeatureBranch) IsHibernatedNamespaceToBeDeleted(staleFeatureBranch featurebranchv2.StaleFeatureBranch, namespace corev1.Namespace) (bool, error) {
+	if staleFeatureBranch.Spec.DeleteAfter.Duration == 0 {
+		return false, nil
+	}
+
+	if debugIsEnabled == os.Getenv("IS_DEBUG") {
+		return true, nil
+	}
+
+	lastActivity, err := r.GetNamespaceLastActivity(staleFeatureBranch.Spec, namespace)
+
+	if err != nil {
+		return false, err
+	}
+
+	return metav1.Now().Sub(lastActivity.Time) >= staleFeatureBranch.Spec.DeleteAfter.Duration, nil
+}
+
+// HibernateNamespace scales the namespace's deployments and stateful sets to zero and suspends its cron jobs. Original
+// replicas and suspensions are recorded to the workloads' annotations to be restored on wake. Workloads and cron jobs
+// are listed from the API server, so the informer cache doesn't start cluster-wide informers of them.
+func (r *ReconcileStaleFeatureBranch) HibernateNamespace(namespace *corev1.Namespace, hibernateTime time.Time) error {
+	inNamespace := client.InNamespace(namespace.Name)
+	zeroReplicas := int32(0)
+
+	var deployments appsv1.DeploymentList
+
+	if err := r.getAPIReader().List(context.TODO(), &deployments, inNamespace); err != nil {
+		return err
+	}
+
+	for index := range deployments.Items {
+		deployment := &deployments.Items[index]
+		original := deployment.DeepCopy()
+
+		if _, isHibernated := deployment.Annotations[featurebranch.OriginalReplicasAnnotation]; isHibernated {
+			continue
+		}
+
+		setAnnotation(&deployment.ObjectMeta, featurebranch.OriginalReplicasAnnotation, formatReplicas(deployment.Spec.Replicas))
+		deployment.Spec.Replicas = &zeroReplicas
+
+		if err := r.Client.Patch(context.TODO(), deployment, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	var statefulSets appsv1.StatefulSetList
+
+	if err := r.getAPIReader().List(context.TODO(), &statefulSets, inNamespace); err != nil {
+		return err
+	}
+
+	for index := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[index]
+		original := statefulSet.DeepCopy()
+
+		if _, isHibernated := statefulSet.Annotations[featurebranch.OriginalReplicasAnnotation]; isHibernated {
+			continue
+		}
+
+		setAnnotation(&statefulSet.ObjectMeta, featurebranch.OriginalReplicasAnnotation, formatReplicas(statefulSet.Spec.Replicas))
+		statefulSet.Spec.Replicas = &zeroReplicas
+
+		if err := r.Client.Patch(context.TODO(), statefulSet, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	var cronJobs batchv1beta1.CronJobList
+
+	if err := r.getAPIReader().List(context.TODO(), &cronJobs, inNamespace); err != nil {
+		return err
+	}
+
+	for index := range cronJobs.Items {
+		cronJob := &cronJobs.Items[index]
+		original := cronJob.DeepCopy()
+		isSuspended := cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend
+		isSuspendedNow := true
+
+		if _, isHibernated := cronJob.Annotations[featurebranch.OriginalSuspendAnnotation]; isHibernated {
+			continue
+		}
+
+		setAnnotation(&cronJob.ObjectMeta, featurebranch.OriginalSuspendAnnotation, strconv.FormatBool(isSuspended))
+		cronJob.Spec.Suspend = &isSuspendedNow
+
+		if err := r.Client.Patch(context.TODO(), cronJob, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	original := namespace.DeepCopy()
+	setAnnotation(&namespace.ObjectMeta, featurebranch.HibernatedAtAnnotation, hibernateTime.UTC().Format(time.RFC3339))
+
+	return r.Client.Patch(context.TODO(), namespace, client.MergeFrom(original))
+}
+
+// WakeNamespace restores the namespace's workloads' replicas and cron jobs' suspensions recorded on hibernation and
+// annotates the namespace as woken at the time, so the wake counts as a deploy.
+func (r *ReconcileStaleFeatureBranch) WakeNamespace(namespace *corev1.Namespace, wakeTime time.Time) error {
+	inNamespace := client.InNamespace(namespace.Name)
+
+	var deployments appsv1.DeploymentList
+
+	if err := r.getAPIReader().List(context.TODO(), &deployments, inNamespace); err != nil {
+		return err
+	}
+
+	for index := range deployments.Items {
+		deployment := &deployments.Items[index]
+		original := deployment.DeepCopy()
+
+		originalReplicas, isHibernated := deployment.Annotations[featurebranch.OriginalReplicasAnnotation]
+
+		if !isHibernated {
+			continue
+		}
+
+		replicas, err := parseReplicas(originalReplicas)
+
+		if err != nil {
+			return fmt.Errorf("unable to restore deployment %s: %v", deployment.Name, err)
+		}
+
+		delete(deployment.Annotations, featurebranch.OriginalReplicasAnnotation)
+		deployment.Spec.Replicas = &replicas
+
+		if err := r.Client.Patch(context.TODO(), deployment, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	var statefulSets appsv1.StatefulSetList
+
+	if err := r.getAPIReader().List(context.TODO(), &statefulSets, inNamespace); err != nil {
+		return err
+	}
+
+	for index := range statefulSets.Items {
+		statefulSet := &statefulSets.Items[index]
+		original := statefulSet.DeepCopy()
+
+		originalReplicas, isHibernated := statefulSet.Annotations[featurebranch.OriginalReplicasAnnotation]
+
+		if !isHibernated {
+			continue
+		}
+
+		replicas, err := parseReplicas(originalReplicas)
+
+		if err != nil {
+			return fmt.Errorf("unable to restore stateful set %s: %v", statefulSet.Name, err)
+		}
+
+		delete(statefulSet.Annotations, featurebranch.OriginalReplicasAnnotation)
+		statefulSet.Spec.Replicas = &replicas
+
+		if err := r.Client.Patch(context.TODO(), statefulSet, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	var cronJobs batchv1beta1.CronJobList
+
+	if err := r.getAPIReader().List(context.TODO(), &cronJobs, inNamespace); err != nil {
+		return err
+	}
+
+	for index := range cronJobs.Items {
+		cronJob := &cronJobs.Items[index]
+		original := cronJob.DeepCopy()
+
+		originalSuspend, isHibernated := cronJob.Annotations[featurebranch.OriginalSuspendAnnotation]
+
+		if !isHibernated {
+			continue
+		}
+
+		isSuspended, err := strconv.ParseBool(originalSuspend)
+
+		if err != nil {
+			return fmt.Errorf("unable to restore cron job %s: %v", cronJob.Name, err)
+		}
+
+		delete(cronJob.Annotations, featurebranch.OriginalSuspendAnnotation)
+		cronJob.Spec.Suspend = &isSuspended
+
+		if err := r.Client.Patch(context.TODO(), cronJob, client.MergeFrom(original)); err != nil {
+			return err
+		}
+	}
+
+	original := namespace.DeepCopy()
+
+	delete(namespace.Annotations, featurebranch.HibernatedAtAnnotation)
+	delete(namespace.Annotations, featurebranch.WakeAnnotation)
+	setAnnotation(&namespace.ObjectMeta, featurebranch.WokenAtAnnotation, wakeTime.UTC().Format(time.RFC3339))
+
+	return r.Client.Patch(context.TODO(), namespace, client.MergeFrom(original))
+}
+
+func setAnnotation(objectMeta *metav1.ObjectMeta, key string, value string) {
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = make(map[string]string)
+	}
+
+	objectMeta.Annotations[key] = value
+}
+
+// formatReplicas formats the workload's replicas, absent replicas default to one.
+func formatReplicas(replicas *int32) string {
+	if replicas == nil {
+		return "1"
+	}
+
+	return strconv.Itoa(int(*replicas))
+}
+
+func parseReplicas(replicas string) (int32, error) {
+	parsedReplicas, err := strconv.ParseInt(replicas, 10, 32)
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid original replicas %q", replicas)
+	}
+
+	return int32(parsedReplicas), nil
+}
@@ -0,0 +1,378 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// forbiddenListClient fails lists as the informer cache does if the operator isn't allowed to watch the resource.
+type forbiddenListClient struct {
+	client.Client
+}
+
+func (c forbiddenListClient) List(ctx context.Context, list runtime.Object, options ...client.ListOption) error {
+	return errors.New("watch is forbidden")
+}
+
+// Case: validate the stale feature branch's action.
+// Where: the second threshold is used without hibernation or isn't longer than the first one.
+// Expected: error is returned.
+func TestValidateAction(t *testing.T) {
+	// Set up data for tests.
+	cases := []struct {
+		spec        featurebranchv2.StaleFeatureBranchSpec
+		isValid     bool
+		description string
+	}{
+		{
+			featurebranchv2.StaleFeatureBranchSpec{StaleAfter: metav1.Duration{Duration: 3 * 24 * time.Hour}},
+			true,
+			"Default action is valid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				Action:      featurebranchv2.HibernateActionType,
+				StaleAfter:  metav1.Duration{Duration: 3 * 24 * time.Hour},
+				DeleteAfter: metav1.Duration{Duration: 14 * 24 * time.Hour},
+			},
+			true,
+			"Hibernation with a longer second threshold is valid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				Action:      featurebranchv2.HibernateActionType,
+				StaleAfter:  metav1.Duration{Duration: 3 * 24 * time.Hour},
+				DeleteAfter: metav1.Duration{Duration: 3 * 24 * time.Hour},
+			},
+			false,
+			"Hibernation with not longer second threshold is invalid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				Action:      featurebranchv2.DeleteActionType,
+				StaleAfter:  metav1.Duration{Duration: 3 * 24 * time.Hour},
+				DeleteAfter: metav1.Duration{Duration: 14 * 24 * time.Hour},
+			},
+			false,
+			"Second threshold without hibernation is invalid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				Action:     "Archive",
+				StaleAfter: metav1.Duration{Duration: 3 * 24 * time.Hour},
+			},
+			false,
+			"Unknown action is invalid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{Action: featurebranchv2.DeleteActionType},
+			false,
+			"Action without stale after is invalid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				StaleAfter:  metav1.Duration{Duration: 3 * 24 * time.Hour},
+				GracePeriod: metav1.Duration{Duration: -time.Hour},
+			},
+			false,
+			"Negative grace period is invalid.",
+		},
+		{
+			featurebranchv2.StaleFeatureBranchSpec{
+				StaleAfter:            metav1.Duration{Duration: 3 * 24 * time.Hour},
+				StuckTerminatingAfter: metav1.Duration{Duration: -time.Hour},
+			},
+			false,
+			"Negative stuck terminating after is invalid.",
+		},
+	}
+
+	// Testing.
+	for _, testCase := range cases {
+		err := ValidateAction(testCase.spec)
+		assert.Equal(t, testCase.isValid, err == nil, testCase.description)
+	}
+}
+
+// Case: hibernate stale feature branches and wake them.
+// Where: stale namespace contains a deployment, a stateful set and a cron job.
+// Expected: workloads are scaled to zero, the cron job is suspended, the namespace isn't deleted. After the namespace
+// is annotated to be woken, original replicas and suspension are restored.
+func TestHibernateAndWakeNamespace(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceName      = "project-pr-1"
+		deploymentReplicas = int32(3)
+		reconciler         ReconcileStaleFeatureBranch
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while disabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+			Action:             featurebranchv2.HibernateActionType,
+		},
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaceName,
+		},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "back-end",
+			Namespace: namespaceName,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &deploymentReplicas,
+		},
+	}
+
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "database",
+			Namespace: namespaceName,
+		},
+	}
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "report",
+			Namespace: namespaceName,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		namespace,
+		deployment,
+		statefulSet,
+		cronJob,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	// Testing.
+	if err := reconciler.ProcessNamespaces(staleFeatureBranch, matcher, false); err != nil {
+		t.Fatalf("An error occurred while processing namespaces: (%v)", err)
+	}
+
+	var hibernatedNamespace corev1.Namespace
+	var hibernatedDeployment appsv1.Deployment
+	var hibernatedStatefulSet appsv1.StatefulSet
+	var hibernatedCronJob batchv1beta1.CronJob
+
+	getObject(t, reconciler, types.NamespacedName{Name: namespaceName}, &hibernatedNamespace)
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "back-end"}, &hibernatedDeployment)
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "database"}, &hibernatedStatefulSet)
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "report"}, &hibernatedCronJob)
+
+	assert.Equal(t, []string{namespaceName}, staleFeatureBranch.Status.HibernatedNamespaces, "Namespace is hibernated.")
+	assert.True(t, IsNamespaceHibernated(hibernatedNamespace), "Namespace is annotated as hibernated.")
+	assert.Equal(t, int32(0), *hibernatedDeployment.Spec.Replicas, "Deployment is scaled to zero.")
+	assert.Equal(t, "3", hibernatedDeployment.Annotations[featurebranch.OriginalReplicasAnnotation], "Deployment's replicas are recorded.")
+	assert.Equal(t, int32(0), *hibernatedStatefulSet.Spec.Replicas, "Stateful set is scaled to zero.")
+	assert.Equal(t, "1", hibernatedStatefulSet.Annotations[featurebranch.OriginalReplicasAnnotation], "Stateful set's default replicas are recorded.")
+	assert.True(t, *hibernatedCronJob.Spec.Suspend, "Cron job is suspended.")
+
+	hibernatedNamespace.Annotations[featurebranch.WakeAnnotation] = "true"
+
+	if err := reconciler.Client.Update(context.TODO(), &hibernatedNamespace); err != nil {
+		t.Fatalf("An error occurred while annotating the namespace to be woken: (%v)", err)
+	}
+
+	if err := reconciler.ProcessNamespaces(staleFeatureBranch, matcher, false); err != nil {
+		t.Fatalf("An error occurred while processing namespaces: (%v)", err)
+	}
+
+	var wokenNamespace corev1.Namespace
+	var wokenDeployment appsv1.Deployment
+	var wokenCronJob batchv1beta1.CronJob
+
+	getObject(t, reconciler, types.NamespacedName{Name: namespaceName}, &wokenNamespace)
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "back-end"}, &wokenDeployment)
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "report"}, &wokenCronJob)
+
+	assert.False(t, IsNamespaceHibernated(wokenNamespace), "Namespace isn't hibernated anymore.")
+	assert.NotContains(t, wokenNamespace.Annotations, featurebranch.WakeAnnotation, "Wake annotation is removed.")
+	assert.Contains(t, wokenNamespace.Annotations, featurebranch.WokenAtAnnotation, "Namespace is annotated as woken.")
+	assert.Equal(t, int32(3), *wokenDeployment.Spec.Replicas, "Deployment's replicas are restored.")
+	assert.False(t, *wokenCronJob.Spec.Suspend, "Cron job's suspension is restored.")
+	assert.Equal(t, NotStaleSkipReason, staleFeatureBranch.Status.SkippedNamespaces[0].Reason, "Wake counts as a deploy.")
+}
+
+// Case: hibernate a namespace and wake it.
+// Where: the cached client isn't allowed to list workloads and cron jobs.
+// Expected: the cron job is listed from the API server, suspended on hibernation and restored on wake.
+func TestHibernateAndWakeNamespaceAPIReader(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceName = "project-pr-1"
+		hibernateTime = time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namespaceName,
+		},
+	}
+
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "report",
+			Namespace: namespaceName,
+		},
+	}
+
+	apiReader := fake.NewFakeClientWithScheme(scheme.Scheme, namespace, cronJob)
+
+	reconciler := ReconcileStaleFeatureBranch{
+		Client:    forbiddenListClient{Client: apiReader},
+		APIReader: apiReader,
+		Scheme:    scheme.Scheme,
+	}
+
+	// Testing.
+	if err := reconciler.HibernateNamespace(namespace, hibernateTime); err != nil {
+		t.Fatalf("An error occurred while hibernating the namespace: (%v)", err)
+	}
+
+	var hibernatedCronJob batchv1beta1.CronJob
+
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "report"}, &hibernatedCronJob)
+	assert.True(t, *hibernatedCronJob.Spec.Suspend, "Cron job is suspended.")
+
+	if err := reconciler.WakeNamespace(namespace, hibernateTime.Add(time.Hour)); err != nil {
+		t.Fatalf("An error occurred while waking the namespace: (%v)", err)
+	}
+
+	var wokenCronJob batchv1beta1.CronJob
+
+	getObject(t, reconciler, types.NamespacedName{Namespace: namespaceName, Name: "report"}, &wokenCronJob)
+	assert.False(t, *wokenCronJob.Spec.Suspend, "Cron job's suspension is restored.")
+}
+
+// Case: process hibernated stale feature branches.
+// Where: hibernated namespace is without deploy for longer than the second threshold.
+// Expected: namespace is deleted.
+func TestDeleteHibernatedNamespace(t *testing.T) {
+	// Set up data for tests.
+	var (
+		reconciler ReconcileStaleFeatureBranch
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while disabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+			Action:             featurebranchv2.HibernateActionType,
+			DeleteAfter:        metav1.Duration{Duration: 7 * 24 * time.Hour},
+		},
+	}
+
+	longHibernatedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: metav1.NewTime(time.Now().AddDate(0, 0, -10)),
+			Annotations: map[string]string{
+				featurebranch.HibernatedAtAnnotation: "2010-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	recentlyHibernatedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: metav1.NewTime(time.Now().AddDate(0, 0, -3)),
+			Annotations: map[string]string{
+				featurebranch.HibernatedAtAnnotation: "2010-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		longHibernatedNamespace,
+		recentlyHibernatedNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	// Testing.
+	if err := reconciler.ProcessNamespaces(staleFeatureBranch, matcher, false); err != nil {
+		t.Fatalf("An error occurred while processing namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, []string{"project-pr-1"}, staleFeatureBranch.Status.DeletedNamespaces, "Long hibernated namespace is deleted.")
+	assert.Equal(t, []string{"project-pr-2"}, staleFeatureBranch.Status.HibernatedNamespaces, "Recently hibernated namespace is kept.")
+}
+
+func getObject(t *testing.T, reconciler ReconcileStaleFeatureBranch, name types.NamespacedName, object runtime.Object) {
+	if err := reconciler.Client.Get(context.TODO(), name, object); err != nil {
+		t.Fatalf("An error occurred while fetching an object: (%v)", err)
+	}
+}
@@ -0,0 +1,128 @@
+package stalefeaturebranch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// NamespaceMatcher selects feature branches' namespaces by the stale feature branch's specifications.
+type NamespaceMatcher struct {
+	substring   string
+	pattern     *NamespacePattern
+	selector    labels.Selector
+	annotations map[string]string
+	exclusions  []*NamespacePattern
+}
+
+// NewNamespaceMatcher validates the stale feature branch's specifications and creates a matcher for them.
+func NewNamespaceMatcher(spec featurebranchv2.StaleFeatureBranchSpec) (*NamespaceMatcher, error) {
+	matcher := &NamespaceMatcher{
+		substring:   spec.NamespaceSubstring,
+		selector:    labels.Everything(),
+		annotations: spec.NamespaceAnnotations,
+	}
+
+	if spec.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.NamespaceSelector)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector: %v", err)
+		}
+
+		matcher.selector = selector
+	}
+
+	for _, excludedNamespace := range spec.ExcludeNamespaces {
+		exclusion, err := CompileNamespacePattern(excludedNamespace, featurebranchv2.GlobNamespacePatternType)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid excluded namespace: %v", err)
+		}
+
+		matcher.exclusions = append(matcher.exclusions, exclusion)
+	}
+
+	if spec.NamespaceSubstring == "" && spec.NamespacePattern == "" && matcher.selector.Empty() {
+		return nil, errors.New("either namespace substring, namespace pattern or namespace selector is required")
+	}
+
+	if spec.NamespacePattern != "" {
+		pattern, err := CompileNamespacePattern(spec.NamespacePattern, spec.NamespacePatternType)
+
+		if err != nil {
+			return nil, err
+		}
+
+		matcher.pattern = pattern
+	}
+
+	return matcher, nil
+}
+
+// RestrictToOwner restricts the matcher to namespaces labeled as owned by the namespace, so a namespaced stale feature
+// branch isn't able to manage namespaces its namespace isn't permitted to.
+func (m *NamespaceMatcher) RestrictToOwner(owner string) error {
+	requirement, err := labels.NewRequirement(featurebranch.OwnerLabel, selection.Equals, []string{owner})
+
+	if err != nil {
+		return fmt.Errorf("invalid owner: %v", err)
+	}
+
+	m.selector = m.selector.Add(*requirement)
+
+	return nil
+}
+
+// Match reports whether the namespace is a feature branch's namespace and returns the namespace pattern's capture
+// groups if the pattern is specified.
+func (m *NamespaceMatcher) Match(namespace corev1.Namespace) (map[string]string, bool) {
+	if !strings.Contains(namespace.Name, m.substring) {
+		return nil, false
+	}
+
+	if !m.selector.Matches(labels.Set(namespace.Labels)) {
+		return nil, false
+	}
+
+	for key, value := range m.annotations {
+		if namespaceValue, ok := namespace.Annotations[key]; !ok || namespaceValue != value {
+			return nil, false
+		}
+	}
+
+	if m.pattern != nil {
+		return m.pattern.Match(namespace.Name)
+	}
+
+	return nil, true
+}
+
+// IsExcluded reports whether the namespace matches any of the excluded namespaces' names or globs.
+func (m *NamespaceMatcher) IsExcluded(namespace corev1.Namespace) bool {
+	for _, exclusion := range m.exclusions {
+		if _, isMatched := exclusion.Match(namespace.Name); isMatched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ListOptions returns options to select namespaces by labels on the server side.
+func (m *NamespaceMatcher) ListOptions() []client.ListOption {
+	if m.selector.Empty() {
+		return nil
+	}
+
+	return []client.ListOption{client.MatchingLabelsSelector{Selector: m.selector}}
+}
@@ -0,0 +1,67 @@
+package stalefeaturebranch
+
+import (
+	"testing"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: match namespaces by a namespace substring, labels and annotations.
+// Where: namespaces differ by one of the criteria.
+// Expected: the only namespace meeting all the criteria is matched.
+func TestNamespaceMatcherCombinedCriteria(t *testing.T) {
+	// Set up data for tests.
+	matcher, err := NewNamespaceMatcher(featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "-pr-",
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"preview": "true"},
+		},
+		NamespaceAnnotations: map[string]string{"ci/pipeline": "preview"},
+	})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	namespace := func(name string, labels map[string]string, annotations map[string]string) corev1.Namespace {
+		return corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+		}
+	}
+
+	previewLabels := map[string]string{"preview": "true"}
+	previewAnnotations := map[string]string{"ci/pipeline": "preview"}
+
+	// Testing.
+	_, isMatched := matcher.Match(namespace("project-pr-1", previewLabels, previewAnnotations))
+	assert.True(t, isMatched, "Namespace meeting all the criteria is matched.")
+
+	_, isMatched = matcher.Match(namespace("project", previewLabels, previewAnnotations))
+	assert.False(t, isMatched, "Namespace without the substring isn't matched.")
+
+	_, isMatched = matcher.Match(namespace("project-pr-2", nil, previewAnnotations))
+	assert.False(t, isMatched, "Namespace without the labels isn't matched.")
+
+	_, isMatched = matcher.Match(namespace("project-pr-3", previewLabels, map[string]string{"ci/pipeline": "release"}))
+	assert.False(t, isMatched, "Namespace with a different annotation's value isn't matched.")
+}
+
+// Case: create a namespace matcher.
+// Where: neither namespace substring, nor pattern, nor selector is specified.
+// Expected: error is returned as all the cluster's namespaces would be matched.
+func TestNamespaceMatcherNoCriteria(t *testing.T) {
+	// Testing.
+	_, err := NewNamespaceMatcher(featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSelector: &metav1.LabelSelector{},
+	})
+
+	assert.Error(t, err, "Empty namespace selector isn't enough to select namespaces.")
+}
@@ -0,0 +1,166 @@
+package stalefeaturebranch
+
+import (
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	evaluatedNamespacesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "evaluated_namespaces_total",
+			Help:      "Number of namespaces evaluated by a stale feature branch.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	matchedNamespaces = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "matched_namespaces",
+			Help:      "Number of namespaces matched by a stale feature branch during the last run.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	deletedNamespacesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "deleted_namespaces_total",
+			Help:      "Number of namespaces deleted by a stale feature branch.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	skippedNamespacesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "skipped_namespaces_total",
+			Help:      "Number of matched namespaces skipped by a stale feature branch by reason.",
+		},
+		[]string{"namespace", "name", "reason"},
+	)
+
+	deleteFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "delete_failures_total",
+			Help:      "Number of failed deletions of namespaces by a stale feature branch.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	reconcileDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "reconcile_duration_seconds",
+			Help:      "Duration of a stale feature branch's runs in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"namespace", "name"},
+	)
+
+	matchedNamespaceAgeSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "matched_namespace_age_seconds",
+			Help:      "Age of namespaces matched by a stale feature branch in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(time.Hour.Seconds(), 2, 10),
+		},
+		[]string{"namespace", "name"},
+	)
+
+	nextDeletionSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "next_deletion_seconds",
+			Help:      "Time until the next deletion of a namespace marked by a stale feature branch in seconds.",
+		},
+		[]string{"namespace", "name"},
+	)
+
+	lastSuccessfulRunTimestampSeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "last_successful_run_timestamp_seconds",
+			Help:      "Time of a stale feature branch's last successful run as Unix time.",
+		},
+		[]string{"namespace", "name"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		evaluatedNamespacesTotal,
+		matchedNamespaces,
+		deletedNamespacesTotal,
+		skippedNamespacesTotal,
+		deleteFailuresTotal,
+		reconcileDurationSeconds,
+		matchedNamespaceAgeSeconds,
+		nextDeletionSeconds,
+		lastSuccessfulRunTimestampSeconds,
+	)
+}
+
+// RecordRunMetrics records the stale feature branch's run results from its status and the run's duration.
+func RecordRunMetrics(staleFeatureBranch featurebranchv2.StaleFeatureBranch, runTime time.Time, runErr error) {
+	namespace, name := staleFeatureBranch.Namespace, staleFeatureBranch.Name
+	status := staleFeatureBranch.Status
+
+	reconcileDurationSeconds.WithLabelValues(namespace, name).Observe(time.Since(runTime).Seconds())
+	matchedNamespaces.WithLabelValues(namespace, name).Set(float64(status.MatchedNamespacesCount))
+	deletedNamespacesTotal.WithLabelValues(namespace, name).Add(float64(status.DeletedNamespacesCount))
+
+	for reason, count := range status.SkippedNamespacesReasons {
+		skippedNamespacesTotal.WithLabelValues(namespace, name, reason).Add(float64(count))
+	}
+
+	var nextDeleteAfter time.Time
+
+	for _, markedNamespace := range status.MarkedNamespaces {
+		if nextDeleteAfter.IsZero() || markedNamespace.DeleteAfter.Time.Before(nextDeleteAfter) {
+			nextDeleteAfter = markedNamespace.DeleteAfter.Time
+		}
+	}
+
+	if nextDeleteAfter.IsZero() {
+		nextDeletionSeconds.DeleteLabelValues(namespace, name)
+	} else {
+		nextDeletionSeconds.WithLabelValues(namespace, name).Set(time.Until(nextDeleteAfter).Seconds())
+	}
+
+	if runErr == nil {
+		lastSuccessfulRunTimestampSeconds.WithLabelValues(namespace, name).Set(float64(runTime.Unix()))
+	}
+}
+
+// RecordEvaluatedNamespacesMetrics records the number of namespaces evaluated by the stale feature branch and ages of
+// the matched ones.
+func RecordEvaluatedNamespacesMetrics(staleFeatureBranch featurebranchv2.StaleFeatureBranch, evaluatedNamespacesCount int, matches []NamespaceMatch) {
+	evaluatedNamespacesTotal.WithLabelValues(staleFeatureBranch.Namespace, staleFeatureBranch.Name).Add(float64(evaluatedNamespacesCount))
+
+	for _, match := range matches {
+		matchedNamespaceAgeSeconds.WithLabelValues(staleFeatureBranch.Namespace, staleFeatureBranch.Name).Observe(
+			time.Since(match.Namespace.CreationTimestamp.Time).Seconds(),
+		)
+	}
+}
+
+// RecordDeleteFailureMetrics records the failed deletion of a namespace by the stale feature branch.
+func RecordDeleteFailureMetrics(staleFeatureBranch featurebranchv2.StaleFeatureBranch) {
+	deleteFailuresTotal.WithLabelValues(staleFeatureBranch.Namespace, staleFeatureBranch.Name).Inc()
+}
+
+// ForgetMetrics removes the deleted stale feature branch's gauges, so they aren't reported anymore.
+func ForgetMetrics(staleFeatureBranch types.NamespacedName) {
+	matchedNamespaces.DeleteLabelValues(staleFeatureBranch.Namespace, staleFeatureBranch.Name)
+	nextDeletionSeconds.DeleteLabelValues(staleFeatureBranch.Namespace, staleFeatureBranch.Name)
+	lastSuccessfulRunTimestampSeconds.DeleteLabelValues(staleFeatureBranch.Namespace, staleFeatureBranch.Name)
+}
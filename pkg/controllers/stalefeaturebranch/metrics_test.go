@@ -0,0 +1,81 @@
+package stalefeaturebranch
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Case: record a stale feature branch's run metrics.
+// Where: the run succeeded, some namespaces are deleted, skipped and marked.
+// Expected: metrics correspond to the status, metrics are removed after the stale feature branch is deleted.
+func TestRecordRunMetrics(t *testing.T) {
+	// Set up data for tests.
+	var (
+		runTime            = time.Now()
+		deleteAfter        = metav1.NewTime(runTime.Add(time.Hour))
+		staleFeatureBranch = featurebranchv2.StaleFeatureBranch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "metrics-stale-feature-branch",
+				Namespace: "stale-feature-branch-operator",
+			},
+			Status: featurebranchv2.StaleFeatureBranchStatus{
+				MatchedNamespacesCount: 4,
+				DeletedNamespacesCount: 1,
+				SkippedNamespacesReasons: map[string]int{
+					NotStaleSkipReason:          2,
+					MarkedForDeletionSkipReason: 1,
+				},
+				MarkedNamespaces: []featurebranchv2.MarkedNamespace{
+					{Name: "project-pr-4", DeleteAfter: deleteAfter},
+				},
+			},
+		}
+		labels = []string{staleFeatureBranch.Namespace, staleFeatureBranch.Name}
+	)
+
+	// Testing.
+	RecordRunMetrics(staleFeatureBranch, runTime, nil)
+
+	assert.Equal(t, 4.0, testutil.ToFloat64(matchedNamespaces.WithLabelValues(labels...)), "Matched namespaces are recorded.")
+	assert.Equal(t, 1.0, testutil.ToFloat64(deletedNamespacesTotal.WithLabelValues(labels...)), "Deleted namespaces are recorded.")
+	assert.Equal(
+		t,
+		2.0,
+		testutil.ToFloat64(skippedNamespacesTotal.WithLabelValues(append(labels, NotStaleSkipReason)...)),
+		"Skipped namespaces are recorded by reason.",
+	)
+	assert.InDelta(
+		t,
+		time.Hour.Seconds(),
+		testutil.ToFloat64(nextDeletionSeconds.WithLabelValues(labels...)),
+		time.Minute.Seconds(),
+		"Time until the next deletion is recorded.",
+	)
+	assert.Equal(
+		t,
+		float64(runTime.Unix()),
+		testutil.ToFloat64(lastSuccessfulRunTimestampSeconds.WithLabelValues(labels...)),
+		"Time of the last successful run is recorded.",
+	)
+
+	RecordRunMetrics(staleFeatureBranch, runTime.Add(time.Minute), errors.New("namespaces are forbidden"))
+
+	assert.Equal(
+		t,
+		float64(runTime.Unix()),
+		testutil.ToFloat64(lastSuccessfulRunTimestampSeconds.WithLabelValues(labels...)),
+		"Time of the last successful run isn't changed by a failed run.",
+	)
+
+	ForgetMetrics(types.NamespacedName{Namespace: staleFeatureBranch.Namespace, Name: staleFeatureBranch.Name})
+
+	assert.False(t, matchedNamespaces.DeleteLabelValues(labels...), "Deleted stale feature branch's gauges are removed.")
+}
@@ -0,0 +1,163 @@
+package stalefeaturebranch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/notification"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ValidateNotifications validates the stale feature branch's notifications' sinks and templates.
+func ValidateNotifications(spec featurebranchv2.StaleFeatureBranchSpec) error {
+	if spec.Notifications == nil {
+		return nil
+	}
+
+	if len(spec.Notifications.Sinks) == 0 {
+		return errors.New("at least one notification sink is required")
+	}
+
+	for _, sink := range spec.Notifications.Sinks {
+		switch sink.Type {
+		case featurebranchv2.SlackNotificationSinkType, featurebranchv2.TeamsNotificationSinkType, featurebranchv2.WebhookNotificationSinkType:
+			if sink.URL == "" && sink.CredentialsSecret == "" {
+				return fmt.Errorf("%s notification sink requires url or credentials secret", sink.Type)
+			}
+		case featurebranchv2.SMTPNotificationSinkType:
+			if sink.SMTP == nil || sink.SMTP.Address == "" || sink.SMTP.From == "" || len(sink.SMTP.To) == 0 {
+				return errors.New("SMTP notification sink requires address, from and to")
+			}
+		default:
+			return fmt.Errorf("unknown notification sink %q", sink.Type)
+		}
+	}
+
+	if _, err := notification.NewTemplate(spec.Notifications.WarningTemplate); err != nil {
+		return fmt.Errorf("invalid warning template: %v", err)
+	}
+
+	if _, err := notification.NewTemplate(spec.Notifications.DeletedTemplate); err != nil {
+		return fmt.Errorf("invalid deleted template: %v", err)
+	}
+
+	return nil
+}
+
+// NewNotificationSinks creates sinks of the stale feature branch's notifications. Webhooks' URLs are read from the
+// secret's url key and SMTP credentials from its username and password keys if the secret is specified.
+func (r *ReconcileStaleFeatureBranch) NewNotificationSinks(staleFeatureBranch featurebranchv2.StaleFeatureBranch) ([]notification.Sink, error) {
+	httpClient := &http.Client{Timeout: notificationTimeoutSeconds * time.Second}
+	sinks := make([]notification.Sink, 0, len(staleFeatureBranch.Spec.Notifications.Sinks))
+
+	for _, sinkSpec := range staleFeatureBranch.Spec.Notifications.Sinks {
+		webhookURL := sinkSpec.URL
+
+		var username, password string
+
+		if sinkSpec.CredentialsSecret != "" {
+			credentials, err := r.getCredentialsSecret(staleFeatureBranch, sinkSpec.CredentialsSecret)
+
+			if err != nil {
+				return nil, fmt.Errorf("unable to fetch %s notification sink credentials: %v", sinkSpec.Type, err)
+			}
+
+			if secretURL, isSet := credentials.Data[notificationURLSecretKey]; isSet {
+				webhookURL = string(secretURL)
+			}
+
+			username = string(credentials.Data[notificationUsernameSecretKey])
+			password = string(credentials.Data[notificationPasswordSecretKey])
+		}
+
+		switch sinkSpec.Type {
+		case featurebranchv2.SlackNotificationSinkType:
+			sinks = append(sinks, &notification.SlackSink{WebhookURL: webhookURL, HTTPClient: httpClient})
+		case featurebranchv2.TeamsNotificationSinkType:
+			sinks = append(sinks, &notification.TeamsSink{WebhookURL: webhookURL, HTTPClient: httpClient})
+		case featurebranchv2.WebhookNotificationSinkType:
+			sinks = append(sinks, &notification.WebhookSink{URL: webhookURL, HTTPClient: httpClient})
+		case featurebranchv2.SMTPNotificationSinkType:
+			sinks = append(sinks, &notification.SMTPSink{
+				Address:  sinkSpec.SMTP.Address,
+				Username: username,
+				Password: password,
+				From:     sinkSpec.SMTP.From,
+				To:       sinkSpec.SMTP.To,
+			})
+		default:
+			return nil, fmt.Errorf("unknown notification sink %q", sinkSpec.Type)
+		}
+	}
+
+	return sinks, nil
+}
+
+// NewNotificationNamespace describes the namespace for notifications: its age at the time, its pull request's number
+// if pull requests are specified and the duration it will be deleted in.
+func NewNotificationNamespace(spec featurebranchv2.StaleFeatureBranchSpec, namespace corev1.Namespace, now time.Time, deleteIn time.Duration) notification.Namespace {
+	notificationNamespace := notification.Namespace{
+		Name:     namespace.Name,
+		Age:      notification.Duration(now.Sub(namespace.CreationTimestamp.Time)),
+		DeleteIn: notification.Duration(deleteIn),
+	}
+
+	if spec.PullRequest != nil {
+		if number, isNumbered, err := GetPullRequestNumber(*spec.PullRequest, namespace); err == nil && isNumbered {
+			notificationNamespace.PullRequestNumber = number
+		}
+	}
+
+	return notificationNamespace
+}
+
+// Notify sends the notification about the namespaces to every sink of the stale feature branch. Failed notifications
+// are logged and recorded as events, but they don't fail the run.
+func (r *ReconcileStaleFeatureBranch) Notify(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, kind notification.Kind, namespaces []notification.Namespace) {
+	notificationSpec := staleFeatureBranch.Spec.Notifications
+
+	if notificationSpec == nil || len(namespaces) == 0 {
+		return
+	}
+
+	message := notification.Notification{
+		Kind:               kind,
+		StaleFeatureBranch: DescribeStaleFeatureBranch(*staleFeatureBranch),
+		Namespaces:         namespaces,
+	}
+
+	if err := message.Render(notificationSpec.WarningTemplate, notificationSpec.DeletedTemplate); err != nil {
+		r.recordNotificationFailure(staleFeatureBranch, kind, err)
+		return
+	}
+
+	sinks, err := r.NewNotificationSinks(*staleFeatureBranch)
+
+	if err != nil {
+		r.recordNotificationFailure(staleFeatureBranch, kind, err)
+		return
+	}
+
+	for _, sink := range sinks {
+		if err := sink.Send(message); err != nil {
+			r.recordNotificationFailure(staleFeatureBranch, kind, err)
+		}
+	}
+}
+
+func (r *ReconcileStaleFeatureBranch) recordNotificationFailure(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, kind notification.Kind, err error) {
+	logger.Error(err, "An error occurred while send a notification.", "kind", kind)
+
+	r.recordEvent(
+		staleFeatureBranch,
+		corev1.EventTypeWarning,
+		NotificationFailedEventReason,
+		"Unable to send %s notification: %v.",
+		kind,
+		err,
+	)
+}
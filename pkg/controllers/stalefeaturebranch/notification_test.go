@@ -0,0 +1,207 @@
+package stalefeaturebranch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"bou.ke/monkey"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Case: notify about stale feature branches' namespaces.
+// Where: grace period is specified, one namespace is unmarked and another is marked long ago, generic webhook's URL is
+// stored in the secret, pull requests of both namespaces are merged.
+// Expected: warning about the newly marked namespace and summary of the deleted namespace are posted to the webhook
+// with the namespaces' ages and pull requests' numbers.
+func TestReconcilerStaleFeatureBranchesNotifications(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace = "stale-feature-branch-operator"
+		namespaceCreationTimestamp  = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		currentTimestamp            = time.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+		notifications               []map[string]interface{}
+		reconciler                  ReconcileStaleFeatureBranch
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while disabling debug: (%v)", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/notifications" {
+			fmt.Fprint(writer, `{"state": "closed", "merged": true}`)
+			return
+		}
+
+		var body map[string]interface{}
+
+		payload, _ := ioutil.ReadAll(request.Body)
+
+		if err := json.Unmarshal(payload, &body); err != nil {
+			t.Errorf("An error occurred while decoding a notification: (%v)", err)
+		}
+
+		notifications = append(notifications, body)
+	}))
+
+	defer server.Close()
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: time.Minute},
+			GracePeriod:        metav1.Duration{Duration: time.Hour},
+			PullRequest: &featurebranchv2.PullRequestSpec{
+				Provider:      featurebranchv2.GitHubProviderType,
+				URL:           server.URL,
+				Repository:    "owner/project",
+				NumberPattern: `-pr-(\d+)$`,
+			},
+			Notifications: &featurebranchv2.NotificationSpec{
+				Sinks: []featurebranchv2.NotificationSinkSpec{
+					{Type: featurebranchv2.WebhookNotificationSinkType, CredentialsSecret: "notifications"},
+				},
+			},
+		},
+	}
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "notifications",
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Data: map[string][]byte{
+			"url": []byte(server.URL + "/notifications"),
+		},
+	}
+
+	unmarkedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	markedLongAgoNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: namespaceCreationTimestamp,
+			Annotations: map[string]string{
+				featurebranch.MarkedForDeletionAtAnnotation: "2010-01-10T10:00:00Z",
+			},
+			Labels: map[string]string{
+				featurebranch.MarkedForDeletionLabel: "true",
+			},
+		},
+	}
+
+	objects := []runtime.Object{staleFeatureBranch, credentials, unmarkedNamespace, markedLongAgoNamespace}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	if !assert.Len(t, notifications, 2, "Warning and summary are posted.") {
+		return
+	}
+
+	assert.Equal(t, "Warning", notifications[0]["kind"], "Warning is posted first.")
+	assert.Equal(
+		t,
+		[]interface{}{
+			map[string]interface{}{"name": "project-pr-1", "age": "9d12h", "pullRequestNumber": float64(1), "deleteIn": "1h"},
+		},
+		notifications[0]["namespaces"],
+		"Warning is about the newly marked namespace.",
+	)
+	assert.Equal(
+		t,
+		"Namespace project-pr-1 of pull request #1 is 9d12h old and will be deleted in 1h by stale feature branch "+
+			"stale-feature-branch-operator/stale-feature-branch-operator.",
+		notifications[0]["text"],
+		"Warning is rendered by the default template.",
+	)
+
+	assert.Equal(t, "Deleted", notifications[1]["kind"], "Summary is posted after the warning.")
+	assert.Equal(
+		t,
+		[]interface{}{map[string]interface{}{"name": "project-pr-2", "age": "9d12h", "pullRequestNumber": float64(2)}},
+		notifications[1]["namespaces"],
+		"Summary is about the deleted namespace.",
+	)
+}
+
+// Case: validate notifications' specifications.
+// Where: sinks are missing, sink is unknown, webhook has no URL, SMTP has no recipients, template is invalid.
+// Expected: an error is returned.
+func TestValidateNotificationsInvalid(t *testing.T) {
+	// Set up data for tests.
+	invalidNotificationSpecs := []*featurebranchv2.NotificationSpec{
+		{},
+		{Sinks: []featurebranchv2.NotificationSinkSpec{{Type: "Discord", URL: "https://discord.example.com"}}},
+		{Sinks: []featurebranchv2.NotificationSinkSpec{{Type: featurebranchv2.SlackNotificationSinkType}}},
+		{
+			Sinks: []featurebranchv2.NotificationSinkSpec{
+				{
+					Type: featurebranchv2.SMTPNotificationSinkType,
+					SMTP: &featurebranchv2.SMTPNotificationSpec{Address: "smtp.example.com:587", From: "operator@example.com"},
+				},
+			},
+		},
+		{
+			Sinks:           []featurebranchv2.NotificationSinkSpec{{Type: featurebranchv2.TeamsNotificationSinkType, URL: "https://teams.example.com"}},
+			WarningTemplate: "{{ range .Namespaces }}",
+		},
+	}
+
+	// Testing.
+	assert.NoError(t, ValidateNotifications(featurebranchv2.StaleFeatureBranchSpec{}), "Notifications are optional.")
+
+	for _, notificationSpec := range invalidNotificationSpecs {
+		err := ValidateNotifications(featurebranchv2.StaleFeatureBranchSpec{Notifications: notificationSpec})
+		assert.Error(t, err, "Misconfigured notifications are rejected.")
+	}
+}
@@ -0,0 +1,133 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+)
+
+// NamespacePattern matches the whole namespace's name against a regular expression or a shell glob and extracts
+// the pattern's capture groups. Glob's wildcards are capture groups as well.
+type NamespacePattern struct {
+	expression *regexp.Regexp
+}
+
+// CompileNamespacePattern compiles the pattern of the type. Regular expressions are anchored to the beginning and the
+// end of the namespace's name.
+func CompileNamespacePattern(pattern string, patternType featurebranchv2.NamespacePatternType) (*NamespacePattern, error) {
+	var expression string
+
+	switch patternType {
+	case featurebranchv2.RegexpNamespacePatternType, "":
+		expression = pattern
+	case featurebranchv2.GlobNamespacePatternType:
+		globExpression, err := convertGlobToRegexp(pattern)
+
+		if err != nil {
+			return nil, err
+		}
+
+		expression = globExpression
+	default:
+		return nil, fmt.Errorf("unknown namespace pattern type %q", patternType)
+	}
+
+	compiledExpression, err := regexp.Compile("^(?:" + expression + ")$")
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace pattern %q: %v", pattern, err)
+	}
+
+	return &NamespacePattern{expression: compiledExpression}, nil
+}
+
+// Match reports whether the namespace's name matches the pattern and returns the pattern's capture groups. Named
+// groups are returned by their names, other groups by their positions starting from 1.
+func (p *NamespacePattern) Match(namespaceName string) (map[string]string, bool) {
+	submatches := p.expression.FindStringSubmatch(namespaceName)
+
+	if submatches == nil {
+		return nil, false
+	}
+
+	captures := make(map[string]string)
+
+	for index, groupName := range p.expression.SubexpNames() {
+		if index == 0 {
+			continue
+		}
+
+		if groupName == "" {
+			groupName = strconv.Itoa(index)
+		}
+
+		captures[groupName] = submatches[index]
+	}
+
+	return captures, true
+}
+
+// FormatCaptures formats capture groups as a sorted list of key-value pairs for logs and events.
+func FormatCaptures(captures map[string]string) string {
+	var pairs []string
+
+	for groupName, value := range captures {
+		pairs = append(pairs, groupName+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ", ")
+}
+
+func convertGlobToRegexp(glob string) (string, error) {
+	var expression strings.Builder
+
+	for index := 0; index < len(glob); index++ {
+		character := glob[index]
+
+		switch character {
+		case '*':
+			expression.WriteString("(.*)")
+		case '?':
+			expression.WriteString("(.)")
+		case '[':
+			end := strings.IndexByte(glob[index+1:], ']')
+
+			if end == -1 {
+				return "", fmt.Errorf("invalid namespace pattern %q: unclosed character class", glob)
+			}
+
+			class := glob[index+1 : index+1+end]
+
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			expression.WriteString("([" + class + "])")
+			index += end + 1
+		case '\\':
+			if index+1 < len(glob) {
+				index++
+			}
+
+			expression.WriteString(regexp.QuoteMeta(string(glob[index])))
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(character)))
+		}
+	}
+
+	return expression.String(), nil
+}
+
+func formatEventCaptures(captures map[string]string) string {
+	if len(captures) == 0 {
+		return ""
+	}
+
+	return " Captures: " + FormatCaptures(captures) + "."
+}
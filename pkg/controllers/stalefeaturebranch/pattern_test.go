@@ -0,0 +1,78 @@
+package stalefeaturebranch
+
+import (
+	"testing"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Case: match namespaces against a regular expression.
+// Where: the expression contains named and unnamed capture groups.
+// Expected: the expression is anchored, capture groups are returned by names and positions.
+func TestNamespacePatternRegexp(t *testing.T) {
+	// Set up data for tests.
+	pattern, err := CompileNamespacePattern(`(?P<project>[a-z-]+)-pr-(\d+)`, featurebranchv2.RegexpNamespacePatternType)
+
+	if err != nil {
+		t.Fatalf("An error occurred while compiling the namespace pattern: (%v)", err)
+	}
+
+	// Testing.
+	captures, isMatched := pattern.Match("github-back-end-pr-17")
+
+	assert.True(t, isMatched, "Feature branch's namespace matches the pattern.")
+	assert.Equal(
+		t,
+		map[string]string{"project": "github-back-end", "2": "17"},
+		captures,
+		"Capture groups are returned by names and positions.",
+	)
+
+	_, isMatched = pattern.Match("ops-pr-tools")
+	assert.False(t, isMatched, "Namespace containing the pattern in the middle doesn't match as it's anchored.")
+}
+
+// Case: match namespaces against a shell glob.
+// Where: the glob contains wildcards and a character class.
+// Expected: wildcards are capture groups, the whole namespace's name should match.
+func TestNamespacePatternGlob(t *testing.T) {
+	// Set up data for tests.
+	pattern, err := CompileNamespacePattern("*-pr-[0-9]*", featurebranchv2.GlobNamespacePatternType)
+
+	if err != nil {
+		t.Fatalf("An error occurred while compiling the namespace pattern: (%v)", err)
+	}
+
+	// Testing.
+	captures, isMatched := pattern.Match("project-pr-17")
+
+	assert.True(t, isMatched, "Feature branch's namespace matches the glob.")
+	assert.Equal(
+		t,
+		map[string]string{"1": "project", "2": "1", "3": "7"},
+		captures,
+		"Wildcards are returned as capture groups.",
+	)
+
+	_, isMatched = pattern.Match("ops-pr-tools")
+	assert.False(t, isMatched, "Namespace without a number after the substring doesn't match.")
+
+	assert.Equal(t, "1=project, 2=1, 3=7", FormatCaptures(captures), "Captures are formatted in sorted order.")
+}
+
+// Case: compile namespace patterns.
+// Where: patterns are invalid.
+// Expected: errors are returned.
+func TestNamespacePatternInvalid(t *testing.T) {
+	// Testing.
+	_, err := CompileNamespacePattern("project-pr-(", featurebranchv2.RegexpNamespacePatternType)
+	assert.Error(t, err, "Regular expression with unclosed group is invalid.")
+
+	_, err = CompileNamespacePattern("project-pr-[0-9", featurebranchv2.GlobNamespacePatternType)
+	assert.Error(t, err, "Glob with unclosed character class is invalid.")
+
+	_, err = CompileNamespacePattern("project-pr-*", "Wildcard")
+	assert.Error(t, err, "Unknown pattern type is invalid.")
+}
@@ -0,0 +1,82 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetProtectedNamespaces returns the system namespaces and the operator's own namespace if it's known.
+func GetProtectedNamespaces() []string {
+	protectedNamespaces := ProtectedNamespaces
+
+	if operatorNamespace := os.Getenv("OPERATOR_NAMESPACE"); operatorNamespace != "" {
+		protectedNamespaces = append(protectedNamespaces[:len(protectedNamespaces):len(protectedNamespaces)], operatorNamespace)
+	}
+
+	return protectedNamespaces
+}
+
+// GetNamespaceProtection returns the reason and the message why the namespace is protected from deletion. Namespace is
+// protected if it's a system namespace, the operator's or the stale feature branch's own namespace, is excluded by the
+// specifications or is kept by annotations. Empty reason means the namespace isn't protected.
+func GetNamespaceProtection(staleFeatureBranch featurebranchv2.StaleFeatureBranch, matcher *NamespaceMatcher, namespace corev1.Namespace) (string, string) {
+	for _, protectedNamespace := range GetProtectedNamespaces() {
+		if namespace.Name == protectedNamespace {
+			return ProtectedSkipReason, "Namespace is a system namespace or contains the operator itself."
+		}
+	}
+
+	if namespace.Name == staleFeatureBranch.Namespace {
+		return ProtectedSkipReason, "Namespace contains the stale feature branch itself."
+	}
+
+	if matcher.IsExcluded(namespace) {
+		return ExcludedSkipReason, "Namespace is excluded by the specifications."
+	}
+
+	if namespace.Annotations[featurebranch.KeepAnnotation] == keepAnnotationIsEnabled {
+		return KeepAnnotationSkipReason, fmt.Sprintf("Namespace is annotated with %s.", featurebranch.KeepAnnotation)
+	}
+
+	keepUntil, isKeptUntil := namespace.Annotations[featurebranch.KeepUntilAnnotation]
+
+	if !isKeptUntil {
+		return "", ""
+	}
+
+	keepUntilTime, err := parseKeepUntil(keepUntil)
+
+	if err != nil {
+		return KeepUntilAnnotationSkipReason, fmt.Sprintf(
+			"Namespace is annotated with %s, but its value is invalid: %v.", featurebranch.KeepUntilAnnotation, err,
+		)
+	}
+
+	if metav1.Now().Time.Before(keepUntilTime) {
+		return KeepUntilAnnotationSkipReason, fmt.Sprintf("Namespace is kept until %s.", keepUntil)
+	}
+
+	return "", ""
+}
+
+// parseKeepUntil parses the time in RFC 3339 format or the date. Namespace is kept during the whole date.
+func parseKeepUntil(keepUntil string) (time.Time, error) {
+	if keepUntilTime, err := time.Parse(time.RFC3339, keepUntil); err == nil {
+		return keepUntilTime, nil
+	}
+
+	keepUntilDate, err := time.Parse(keepUntilDateLayout, keepUntil)
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected %s or RFC 3339 time, got %q", keepUntilDateLayout, keepUntil)
+	}
+
+	return keepUntilDate.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,150 @@
+package stalefeaturebranch
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: get namespace's protection from deletion.
+// Where: namespaces are system, excluded, annotated to be kept or not protected at all.
+// Expected: corresponding reasons are returned, not protected namespace has no reason.
+func TestGetNamespaceProtection(t *testing.T) {
+	// Set up data for tests.
+	var (
+		currentTimestamp = time.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+	)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	staleFeatureBranch := featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-",
+			ExcludeNamespaces:  []string{"ops-*", "project-pr-0"},
+		},
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	namespace := func(name string, annotations map[string]string) corev1.Namespace {
+		return corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: annotations,
+			},
+		}
+	}
+
+	cases := []struct {
+		namespace      corev1.Namespace
+		expectedReason string
+		description    string
+	}{
+		{
+			namespace("kube-system", nil),
+			ProtectedSkipReason,
+			"System namespace is protected.",
+		},
+		{
+			namespace("stale-feature-branch-operator", nil),
+			ProtectedSkipReason,
+			"Stale feature branch's own namespace is protected.",
+		},
+		{
+			namespace("ops-pr-tools", nil),
+			ExcludedSkipReason,
+			"Namespace matching excluded glob is excluded.",
+		},
+		{
+			namespace("project-pr-0", nil),
+			ExcludedSkipReason,
+			"Namespace matching excluded name is excluded.",
+		},
+		{
+			namespace("project-pr-1", map[string]string{featurebranch.KeepAnnotation: "true"}),
+			KeepAnnotationSkipReason,
+			"Namespace annotated to be kept is kept.",
+		},
+		{
+			namespace("project-pr-2", map[string]string{featurebranch.KeepUntilAnnotation: "2010-01-10"}),
+			KeepUntilAnnotationSkipReason,
+			"Namespace annotated to be kept until the end of today is kept.",
+		},
+		{
+			namespace("project-pr-3", map[string]string{featurebranch.KeepUntilAnnotation: "2010-01-10T11:00:00Z"}),
+			"",
+			"Namespace annotated to be kept until an hour ago isn't protected.",
+		},
+		{
+			namespace("project-pr-4", map[string]string{featurebranch.KeepUntilAnnotation: "next week"}),
+			KeepUntilAnnotationSkipReason,
+			"Namespace annotated to be kept until an invalid time is kept.",
+		},
+		{
+			namespace("project-pr-5", map[string]string{featurebranch.KeepAnnotation: "false"}),
+			"",
+			"Namespace annotated to not be kept isn't protected.",
+		},
+	}
+
+	// Testing.
+	for _, testCase := range cases {
+		reason, _ := GetNamespaceProtection(staleFeatureBranch, matcher, testCase.namespace)
+		assert.Equal(t, testCase.expectedReason, reason, testCase.description)
+	}
+}
+
+// Case: get protection of the operator's namespace from deletion.
+// Where: the operator's namespace is known, the stale feature branch is in another namespace and matches it.
+// Expected: the operator's namespace is protected, other namespaces aren't.
+func TestGetNamespaceProtectionOperatorNamespace(t *testing.T) {
+	// Set up data for tests.
+	if err := os.Setenv("OPERATOR_NAMESPACE", "ops-stale-feature-branch-operator"); err != nil {
+		t.Fatalf("An error occurred while setting the operator namespace: (%v)", err)
+	}
+
+	defer os.Unsetenv("OPERATOR_NAMESPACE")
+
+	staleFeatureBranch := featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "team-a",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-",
+		},
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	operatorNamespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ops-stale-feature-branch-operator"}}
+	featureBranchNamespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}}
+
+	// Testing.
+	reason, _ := GetNamespaceProtection(staleFeatureBranch, matcher, operatorNamespace)
+	assert.Equal(t, ProtectedSkipReason, reason, "Operator's namespace is protected.")
+
+	reason, _ = GetNamespaceProtection(staleFeatureBranch, matcher, featureBranchNamespace)
+	assert.Equal(t, "", reason, "Feature branch's namespace isn't protected.")
+}
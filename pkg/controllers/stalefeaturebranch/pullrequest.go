@@ -0,0 +1,252 @@
+package stalefeaturebranch
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/gitprovider"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ValidatePullRequest validates the stale feature branch's pull request specifications.
+func ValidatePullRequest(spec featurebranchv2.StaleFeatureBranchSpec) error {
+	if spec.PullRequest == nil {
+		return nil
+	}
+
+	switch spec.PullRequest.Provider {
+	case featurebranchv2.GitHubProviderType, featurebranchv2.GitLabProviderType, featurebranchv2.BitbucketProviderType:
+	default:
+		return fmt.Errorf("unknown git provider %q", spec.PullRequest.Provider)
+	}
+
+	if spec.PullRequest.Repository == "" {
+		return errors.New("pull request repository is required")
+	}
+
+	_, err := newPullRequestNumberPattern(spec.PullRequest.NumberPattern)
+
+	return err
+}
+
+// GetPullRequestNumber extracts the number of the namespace's pull request by the first capture group of the stale
+// feature branch's number pattern. Returns false if the namespace's name doesn't match the pattern.
+func GetPullRequestNumber(pullRequestSpec featurebranchv2.PullRequestSpec, namespace corev1.Namespace) (int, bool, error) {
+	pattern, err := newPullRequestNumberPattern(pullRequestSpec.NumberPattern)
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	submatches := pattern.FindStringSubmatch(namespace.Name)
+
+	if submatches == nil {
+		return 0, false, nil
+	}
+
+	number, err := strconv.Atoi(submatches[1])
+
+	if err != nil {
+		return 0, false, fmt.Errorf("pull request number of namespace %s isn't a number: %q", namespace.Name, submatches[1])
+	}
+
+	return number, true, nil
+}
+
+// NewGitProvider creates the Git hosting provider of the stale feature branch's pull requests. Credentials are read
+// from the secret's token and username keys if the secret is specified. Requests time out, so a hanging provider
+// doesn't block processing.
+func (r *ReconcileStaleFeatureBranch) NewGitProvider(staleFeatureBranch featurebranchv2.StaleFeatureBranch) (gitprovider.Provider, error) {
+	pullRequestSpec := staleFeatureBranch.Spec.PullRequest
+	httpClient := &http.Client{Timeout: gitProviderTimeoutSeconds * time.Second}
+
+	var token, username string
+
+	if pullRequestSpec.CredentialsSecret != "" {
+		credentials, err := r.getCredentialsSecret(staleFeatureBranch, pullRequestSpec.CredentialsSecret)
+
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch git provider credentials: %v", err)
+		}
+
+		token = string(credentials.Data[gitProviderTokenSecretKey])
+		username = string(credentials.Data[gitProviderUsernameSecretKey])
+	}
+
+	switch pullRequestSpec.Provider {
+	case featurebranchv2.GitHubProviderType:
+		return &gitprovider.GitHubProvider{BaseURL: pullRequestSpec.URL, Token: token, HTTPClient: httpClient}, nil
+	case featurebranchv2.GitLabProviderType:
+		return &gitprovider.GitLabProvider{BaseURL: pullRequestSpec.URL, Token: token, HTTPClient: httpClient}, nil
+	case featurebranchv2.BitbucketProviderType:
+		return &gitprovider.BitbucketProvider{
+			BaseURL:    pullRequestSpec.URL,
+			Username:   username,
+			Token:      token,
+			HTTPClient: httpClient,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown git provider %q", pullRequestSpec.Provider)
+}
+
+// GetNamespacePullRequestSkip returns the reason and the message why the namespace is skipped by its pull request:
+// the namespace's pull request is still open or its number is unknown. Empty reason means the pull request is closed
+// or merged. States of pull requests are cached, so runs don't query the provider for every open pull request.
+func (r *ReconcileStaleFeatureBranch) GetNamespacePullRequestSkip(staleFeatureBranch featurebranchv2.StaleFeatureBranch, namespace corev1.Namespace) (string, string, error) {
+	pullRequestSpec := staleFeatureBranch.Spec.PullRequest
+
+	number, isNumbered, err := GetPullRequestNumber(*pullRequestSpec, namespace)
+
+	if err != nil {
+		return "", "", err
+	}
+
+	if !isNumbered {
+		return PullRequestUnknownSkipReason, "Namespace's name doesn't contain a pull request number.", nil
+	}
+
+	state, isCached := r.PullRequestStates.Get(pullRequestSpec.Provider, pullRequestSpec.URL, pullRequestSpec.Repository, number)
+
+	if !isCached {
+		provider, err := r.NewGitProvider(staleFeatureBranch)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		state, err = provider.GetPullRequestState(pullRequestSpec.Repository, number)
+
+		if err != nil {
+			return "", "", fmt.Errorf("unable to get state of pull request #%d: %v", number, err)
+		}
+
+		r.PullRequestStates.Set(pullRequestSpec.Provider, pullRequestSpec.URL, pullRequestSpec.Repository, number, state)
+	}
+
+	if !state.IsFinished() {
+		return PullRequestOpenSkipReason, fmt.Sprintf("Pull request #%d is open.", number), nil
+	}
+
+	return "", "", nil
+}
+
+// PullRequestStateCache caches states of pull requests for a while, so Git hosting providers aren't queried for every
+// open pull request on every run. Pull requests closed by received events are forgotten to be queried again right
+// away. Nil cache caches nothing.
+type PullRequestStateCache struct {
+	MaxAge time.Duration
+
+	mutex  sync.Mutex
+	states map[pullRequestStateKey]cachedPullRequestState
+}
+
+// pullRequestStateKey identifies a pull request by its provider's host, so pull requests of repositories with the same
+// path on different hosts aren't mixed up.
+type pullRequestStateKey struct {
+	provider   featurebranchv2.GitProviderType
+	baseURL    string
+	repository string
+	number     int
+}
+
+type cachedPullRequestState struct {
+	state     gitprovider.PullRequestState
+	fetchTime time.Time
+}
+
+// NewPullRequestStateCache creates the pull request state cache.
+func NewPullRequestStateCache() *PullRequestStateCache {
+	return &PullRequestStateCache{
+		MaxAge: pullRequestStateCacheMaxAge,
+		states: make(map[pullRequestStateKey]cachedPullRequestState),
+	}
+}
+
+// Get returns the cached state of the repository's pull request on the provider's host by its base URL if it's not older
+// than the max age. Empty base URL means the provider's default host.
+func (c *PullRequestStateCache) Get(provider featurebranchv2.GitProviderType, baseURL string, repository string, number int) (gitprovider.PullRequestState, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	cached, isCached := c.states[getPullRequestStateKey(provider, baseURL, repository, number)]
+
+	if !isCached || time.Since(cached.fetchTime) >= c.MaxAge {
+		return "", false
+	}
+
+	return cached.state, true
+}
+
+// Set caches the state of the repository's pull request on the provider's host by its base URL.
+func (c *PullRequestStateCache) Set(provider featurebranchv2.GitProviderType, baseURL string, repository string, number int, state gitprovider.PullRequestState) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, cached := range c.states {
+		if time.Since(cached.fetchTime) >= c.MaxAge {
+			delete(c.states, key)
+		}
+	}
+
+	c.states[getPullRequestStateKey(provider, baseURL, repository, number)] = cachedPullRequestState{state: state, fetchTime: time.Now()}
+}
+
+// Forget forgets cached states of the repository's pull request, e.g. once an event of its closing is received. Events
+// don't tell the provider's base URL, so states of the pull request on all hosts are forgotten to be queried again.
+func (c *PullRequestStateCache) Forget(provider featurebranchv2.GitProviderType, repository string, number int) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	forgotten := getPullRequestStateKey(provider, "", repository, number)
+
+	for key := range c.states {
+		if key.provider == forgotten.provider && key.repository == forgotten.repository && key.number == forgotten.number {
+			delete(c.states, key)
+		}
+	}
+}
+
+// getPullRequestStateKey returns the key of the pull request's state. Repositories and hosts are case-insensitive.
+func getPullRequestStateKey(provider featurebranchv2.GitProviderType, baseURL string, repository string, number int) pullRequestStateKey {
+	return pullRequestStateKey{
+		provider:   provider,
+		baseURL:    strings.TrimSuffix(strings.ToLower(baseURL), "/"),
+		repository: strings.ToLower(repository),
+		number:     number,
+	}
+}
+
+func newPullRequestNumberPattern(numberPattern string) (*regexp.Regexp, error) {
+	pattern, err := regexp.Compile(numberPattern)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid pull request number pattern %q: %v", numberPattern, err)
+	}
+
+	if pattern.NumSubexp() < 1 {
+		return nil, fmt.Errorf("pull request number pattern %q should capture the number by a group", numberPattern)
+	}
+
+	return pattern, nil
+}
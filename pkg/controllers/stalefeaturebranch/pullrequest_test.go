@@ -0,0 +1,247 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/gitprovider"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Case: decide whether namespaces are to be deleted by their pull requests.
+// Where: local stand-in for GitHub responds with merged and open pull requests, one namespace has no number in its name,
+// namespaces are created right now, so they aren't stale yet.
+// Expected: only the namespace of the merged pull request is deleted, the token is read from the secret, states of
+// pull requests are cached between runs.
+func TestIsNamespaceToBeDeletedPullRequest(t *testing.T) {
+	// Set up data for tests.
+	var (
+		authorizations []string
+		reconciler     ReconcileStaleFeatureBranch
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		authorizations = append(authorizations, request.Header.Get("Authorization"))
+
+		switch request.URL.Path {
+		case "/repos/owner/back-end/pulls/1":
+			fmt.Fprint(writer, `{"state": "closed", "merged": true}`)
+		case "/repos/owner/back-end/pulls/2":
+			fmt.Fprint(writer, `{"state": "open", "merged": false}`)
+		default:
+			http.NotFound(writer, request)
+		}
+	}))
+
+	defer server.Close()
+
+	staleFeatureBranch := featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "back-end",
+			StaleAfter:         metav1.Duration{Duration: time.Hour},
+			PullRequest: &featurebranchv2.PullRequestSpec{
+				Provider:          featurebranchv2.GitHubProviderType,
+				URL:               server.URL,
+				Repository:        "owner/back-end",
+				NumberPattern:     `-pr-(\d+)$`,
+				CredentialsSecret: "github",
+			},
+		},
+	}
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "github",
+			Namespace: "stale-feature-branch-operator",
+		},
+		Data: map[string][]byte{
+			"token": []byte("secret"),
+		},
+	}
+
+	matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating the namespace matcher: (%v)", err)
+	}
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:            fake.NewFakeClientWithScheme(scheme.Scheme, credentials),
+		Scheme:            scheme.Scheme,
+		PullRequestStates: NewPullRequestStateCache(),
+	}
+
+	namespace := func(name string) corev1.Namespace {
+		return corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.Now(),
+			},
+		}
+	}
+
+	cases := []struct {
+		namespace        corev1.Namespace
+		expectedDecision NamespaceDecision
+		description      string
+	}{
+		{
+			namespace("back-end-pr-1"),
+			NamespaceDecision{IsToBeDeleted: true},
+			"Namespace of the merged pull request is deleted.",
+		},
+		{
+			namespace("back-end-pr-2"),
+			NamespaceDecision{SkipReason: PullRequestOpenSkipReason, SkipMessage: "Pull request #2 is open."},
+			"Namespace of the open pull request is skipped.",
+		},
+		{
+			namespace("back-end-staging"),
+			NamespaceDecision{
+				SkipReason:  PullRequestUnknownSkipReason,
+				SkipMessage: "Namespace's name doesn't contain a pull request number.",
+			},
+			"Namespace without a pull request number is skipped.",
+		},
+	}
+
+	// Testing.
+	for run := 0; run < 2; run++ {
+		for _, testCase := range cases {
+			decision, err := reconciler.IsNamespaceToBeDeleted(staleFeatureBranch, matcher, testCase.namespace)
+
+			if err != nil {
+				t.Fatalf("An error occurred while deciding whether the namespace is to be deleted: (%v)", err)
+			}
+
+			assert.Equal(t, testCase.expectedDecision, decision, testCase.description)
+		}
+	}
+
+	assert.Equal(
+		t,
+		[]string{"token secret", "token secret"},
+		authorizations,
+		"Token is read from the secret, pull requests are queried once.",
+	)
+}
+
+// Case: create Git hosting providers of pull requests.
+// Where: providers are GitHub, GitLab and Bitbucket.
+// Expected: requests of every provider time out.
+func TestNewGitProviderTimeout(t *testing.T) {
+	// Set up data for tests.
+	reconciler := ReconcileStaleFeatureBranch{
+		Client: fake.NewFakeClientWithScheme(scheme.Scheme),
+		Scheme: scheme.Scheme,
+	}
+
+	providerTypes := []featurebranchv2.GitProviderType{
+		featurebranchv2.GitHubProviderType,
+		featurebranchv2.GitLabProviderType,
+		featurebranchv2.BitbucketProviderType,
+	}
+
+	// Testing.
+	for _, providerType := range providerTypes {
+		provider, err := reconciler.NewGitProvider(featurebranchv2.StaleFeatureBranch{
+			Spec: featurebranchv2.StaleFeatureBranchSpec{
+				PullRequest: &featurebranchv2.PullRequestSpec{Provider: providerType, Repository: "owner/back-end"},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("An error occurred while creating a git provider: (%v)", err)
+		}
+
+		var httpClient *http.Client
+
+		switch provider := provider.(type) {
+		case *gitprovider.GitHubProvider:
+			httpClient = provider.HTTPClient
+		case *gitprovider.GitLabProvider:
+			httpClient = provider.HTTPClient
+		case *gitprovider.BitbucketProvider:
+			httpClient = provider.HTTPClient
+		}
+
+		if assert.NotNil(t, httpClient, fmt.Sprintf("%s provider has an HTTP client.", providerType)) {
+			assert.Equal(t, gitProviderTimeoutSeconds*time.Second, httpClient.Timeout, fmt.Sprintf("%s requests time out.", providerType))
+		}
+	}
+}
+
+// Case: cache states of pull requests.
+// Where: state is cached, expires or is forgotten by a received event.
+// Expected: state is returned until it expires or is forgotten, repositories are case-insensitive, hosts aren't mixed up.
+func TestPullRequestStateCache(t *testing.T) {
+	// Set up data for tests.
+	var nilStates *PullRequestStateCache
+
+	enterpriseURL := "https://github.example.com/api/v3"
+
+	states := NewPullRequestStateCache()
+	states.Set(featurebranchv2.GitHubProviderType, "", "Owner/Back-End", 1, gitprovider.OpenPullRequestState)
+	states.Set(featurebranchv2.GitHubProviderType, "", "owner/back-end", 2, gitprovider.OpenPullRequestState)
+	states.Set(featurebranchv2.GitHubProviderType, enterpriseURL+"/", "owner/back-end", 1, gitprovider.MergedPullRequestState)
+
+	// Testing.
+	state, isCached := states.Get(featurebranchv2.GitHubProviderType, "", "owner/back-end", 1)
+	assert.True(t, isCached, "State is cached.")
+	assert.Equal(t, gitprovider.OpenPullRequestState, state, "Cached state is returned.")
+
+	state, isCached = states.Get(featurebranchv2.GitHubProviderType, enterpriseURL, "owner/back-end", 1)
+	assert.True(t, isCached, "State of other host is cached.")
+	assert.Equal(t, gitprovider.MergedPullRequestState, state, "States of other hosts aren't mixed up.")
+
+	_, isCached = states.Get(featurebranchv2.GitLabProviderType, "", "owner/back-end", 1)
+	assert.False(t, isCached, "States of other providers aren't returned.")
+
+	states.Forget(featurebranchv2.GitHubProviderType, "owner/Back-End", 1)
+	_, isCached = states.Get(featurebranchv2.GitHubProviderType, "", "owner/back-end", 1)
+	assert.False(t, isCached, "Forgotten state isn't returned.")
+	_, isCached = states.Get(featurebranchv2.GitHubProviderType, enterpriseURL, "owner/back-end", 1)
+	assert.False(t, isCached, "Forgotten state of other host isn't returned.")
+
+	states.MaxAge = 0
+	_, isCached = states.Get(featurebranchv2.GitHubProviderType, "", "owner/back-end", 2)
+	assert.False(t, isCached, "Expired state isn't returned.")
+
+	nilStates.Set(featurebranchv2.GitHubProviderType, "", "owner/back-end", 1, gitprovider.OpenPullRequestState)
+	_, isCached = nilStates.Get(featurebranchv2.GitHubProviderType, "", "owner/back-end", 1)
+	assert.False(t, isCached, "Nil cache caches nothing.")
+}
+
+// Case: validate pull request specifications.
+// Where: provider is unknown, repository is missing, number pattern is invalid or doesn't capture the number.
+// Expected: an error is returned.
+func TestValidatePullRequestInvalid(t *testing.T) {
+	// Set up data for tests.
+	invalidPullRequestSpecs := []*featurebranchv2.PullRequestSpec{
+		{Provider: "Gitea", Repository: "owner/back-end", NumberPattern: `-pr-(\d+)$`},
+		{Provider: featurebranchv2.GitHubProviderType, NumberPattern: `-pr-(\d+)$`},
+		{Provider: featurebranchv2.GitHubProviderType, Repository: "owner/back-end", NumberPattern: `-pr-(\d+$`},
+		{Provider: featurebranchv2.GitHubProviderType, Repository: "owner/back-end", NumberPattern: `-pr-\d+$`},
+	}
+
+	// Testing.
+	assert.NoError(t, ValidatePullRequest(featurebranchv2.StaleFeatureBranchSpec{}), "Pull requests are optional.")
+
+	for _, pullRequestSpec := range invalidPullRequestSpecs {
+		err := ValidatePullRequest(featurebranchv2.StaleFeatureBranchSpec{PullRequest: pullRequestSpec})
+		assert.Error(t, err, "Misconfigured pull requests are rejected.")
+	}
+}
@@ -2,15 +2,20 @@ package stalefeaturebranch
 
 import (
 	"context"
-	"strconv"
-	"strings"
+	"fmt"
 	"time"
 
-	featurebranchv1 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v1"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/backup"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/notification"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -19,63 +24,575 @@ import (
 var _ reconcile.Reconciler = &ReconcileStaleFeatureBranch{}
 
 type ReconcileStaleFeatureBranch struct {
-	Client client.Client
-	Scheme *runtime.Scheme
+	Client            client.Client
+	APIReader         client.Reader
+	Scheme            *runtime.Scheme
+	Recorder          record.EventRecorder
+	Exporter          backup.Exporter
+	Budget            *DeletionBudget
+	Triggers          *RunTriggers
+	Batch             *NamespaceBatch
+	PullRequestStates *PullRequestStateCache
 }
 
 func (r *ReconcileStaleFeatureBranch) Reconcile(request reconcile.Request) (reconcile.Result, error) {
-	var staleFeatureBranch featurebranchv1.StaleFeatureBranch
+	var staleFeatureBranch featurebranchv2.StaleFeatureBranch
 
 	if err := r.Client.Get(context.TODO(), request.NamespacedName, &staleFeatureBranch); err != nil {
+		if errors.IsNotFound(err) {
+			ForgetMetrics(request.NamespacedName)
+		}
+
 		logger.Error(err, "Unable to fetch a stale feature branch.")
 		return reconcile.Result{}, nil
 	}
 
+	return r.reconcileStaleFeatureBranch(&staleFeatureBranch, func() error {
+		return r.Client.Status().Update(context.TODO(), &staleFeatureBranch)
+	})
+}
+
+// reconcileStaleFeatureBranch processes feature branches' namespaces by the stale feature branch if its run is due or
+// triggered and saves the run's results by updating the status.
+func (r *ReconcileStaleFeatureBranch) reconcileStaleFeatureBranch(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, updateStatus func() error) (reconcile.Result, error) {
+	var matcher *NamespaceMatcher
+
+	isDryRun := staleFeatureBranch.Spec.DryRun || dryRunIsEnabled == os.Getenv("IS_DRY_RUN")
+
 	logger.Info(
 		"Stale feature branch is being processing.",
 		"namespaceSubstring", staleFeatureBranch.Spec.NamespaceSubstring,
-		"afterDaysWithoutDeploy", staleFeatureBranch.Spec.AfterDaysWithoutDeploy,
-		"checkEveryMinutes", staleFeatureBranch.Spec.CheckEveryMinutes,
+		"namespacePattern", staleFeatureBranch.Spec.NamespacePattern,
+		"namespacePatternType", staleFeatureBranch.Spec.NamespacePatternType,
+		"namespaceSelector", staleFeatureBranch.Spec.NamespaceSelector,
+		"namespaceAnnotations", staleFeatureBranch.Spec.NamespaceAnnotations,
+		"staleAfter", staleFeatureBranch.Spec.StaleAfter.Duration,
+		"interval", staleFeatureBranch.Spec.Interval.Duration,
+		"staleness", staleFeatureBranch.Spec.Staleness,
+		"lastDeployedAtAnnotation", staleFeatureBranch.Spec.LastDeployedAtAnnotation,
+		"pullRequest", staleFeatureBranch.Spec.PullRequest,
+		"notifications", staleFeatureBranch.Spec.Notifications != nil,
+		"action", staleFeatureBranch.Spec.Action,
+		"schedule", staleFeatureBranch.Spec.Schedule,
+		"timeZone", staleFeatureBranch.Spec.TimeZone,
+		"maxDeletionsPerRun", staleFeatureBranch.Spec.MaxDeletionsPerRun,
+		"stuckTerminatingAfter", staleFeatureBranch.Spec.StuckTerminatingAfter.Duration,
+		"clearStuckFinalizers", staleFeatureBranch.Spec.ClearStuckFinalizers,
 		"isDebug", os.Getenv("IS_DEBUG"),
+		"isDryRun", isDryRun,
 	)
 
-	var allNamespaces corev1.NamespaceList
+	runSchedule, err := NewRunSchedule(staleFeatureBranch.Spec)
+
+	if err == nil {
+		err = ValidateAction(staleFeatureBranch.Spec)
+	}
+
+	if err == nil {
+		err = ValidateBackup(staleFeatureBranch.Spec)
+	}
+
+	if err == nil {
+		_, err = NewStalenessPolicy(staleFeatureBranch.Spec)
+	}
+
+	if err == nil {
+		err = ValidateStalenessScope(*staleFeatureBranch)
+	}
+
+	if err == nil {
+		err = ValidatePullRequest(staleFeatureBranch.Spec)
+	}
+
+	if err == nil {
+		err = ValidateNotifications(staleFeatureBranch.Spec)
+	}
+
+	if err == nil {
+		matcher, err = NewNamespaceMatcher(staleFeatureBranch.Spec)
+	}
+
+	if err == nil && !IsClusterScoped(*staleFeatureBranch) {
+		err = matcher.RestrictToOwner(staleFeatureBranch.Namespace)
+	}
+
+	if err != nil {
+		logger.Error(err, "Stale feature branch's specifications are invalid.")
+		SetInvalidSpecificationsStatus(staleFeatureBranch, err)
+
+		r.recordEvent(
+			staleFeatureBranch,
+			corev1.EventTypeWarning,
+			InvalidSpecificationsConditionReason,
+			"Stale feature branch's specifications are invalid: %v.",
+			err,
+		)
+
+		if err := updateStatus(); err != nil {
+			logger.Error(err, "Unable to update the stale feature branch's status.")
+			return reconcile.Result{}, err
+		}
 
-	if err := r.Client.List(context.TODO(), &allNamespaces); err != nil {
-		logger.Error(err, "Unable to fetch the cluster's namespaces.")
 		return reconcile.Result{}, nil
 	}
 
-	for _, namespace := range allNamespaces.Items {
-		if r.IsNamespaceToBeDeleted(staleFeatureBranch, namespace) {
+	runTime := metav1.Now()
+
+	isRunTriggered := r.Triggers.Take(*staleFeatureBranch)
+
+	if dueRunTime, isRunDue := GetDueRunTime(*staleFeatureBranch, runSchedule, runTime.Time); !isRunDue && !isRunTriggered {
+		logger.Info("Stale feature branch's run isn't due yet.", "nextRunTime", dueRunTime)
+		return reconcile.Result{RequeueAfter: dueRunTime.Sub(runTime.Time)}, nil
+	}
+
+	processingErr := r.ProcessNamespaces(staleFeatureBranch, matcher, isDryRun)
+	nextRunTime := metav1.NewTime(GetNextRunTime(staleFeatureBranch.Status, runSchedule, runTime.Time))
+
+	RecordRunMetrics(*staleFeatureBranch, runTime.Time, processingErr)
+
+	SetRunStatus(staleFeatureBranch, runTime, nextRunTime, processingErr)
+
+	if err := updateStatus(); err != nil {
+		logger.Error(err, "Unable to update the stale feature branch's status.")
+		return reconcile.Result{}, err
+	}
+
+	if processingErr != nil {
+		r.recordEvent(
+			staleFeatureBranch,
+			corev1.EventTypeWarning,
+			ProcessingFailedConditionReason,
+			"Processing of feature branches' namespaces failed: %v.",
+			processingErr,
+		)
+
+		return reconcile.Result{}, processingErr
+	}
+
+	return reconcile.Result{RequeueAfter: nextRunTime.Sub(runTime.Time)}, nil
+}
+
+func (r *ReconcileStaleFeatureBranch) ProcessNamespaces(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, matcher *NamespaceMatcher, isDryRun bool) error {
+	status := &staleFeatureBranch.Status
+	previousFailedNamespaces := status.FailedNamespaces
+
+	status.MatchedNamespacesCount = 0
+	status.DeletedNamespacesCount = 0
+	status.SkippedNamespacesCount = 0
+	status.SkippedNamespacesReasons = nil
+	status.MatchedNamespaces = nil
+	status.SkippedNamespaces = nil
+	status.MarkedNamespaces = nil
+	status.HibernatedNamespaces = nil
+	status.BackedUpNamespaces = nil
+	status.DeletedNamespaces = nil
+	status.WouldBeDeletedNamespaces = nil
+	status.StuckNamespaces = nil
+	status.FailedNamespaces = nil
+	status.NextStaleTime = nil
+
+	SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+		Type:   featurebranchv2.StaleFeatureBranchBudgetExceeded,
+		Status: corev1.ConditionFalse,
+		Reason: WithinBudgetConditionReason,
+	})
+
+	matches, evaluatedNamespacesCount, err := r.MatchNamespaces(*staleFeatureBranch, matcher)
+
+	if err != nil {
+		logger.Error(err, "Unable to fetch the cluster's namespaces.")
+		return err
+	}
+
+	RecordEvaluatedNamespacesMetrics(*staleFeatureBranch, evaluatedNamespacesCount, matches)
+
+	var (
+		failures          []error
+		warnedNamespaces  []notification.Namespace
+		deletedNamespaces []notification.Namespace
+	)
+
+	for _, match := range matches {
+		namespace, captures := match.Namespace, match.Captures
+
+		MatchNamespace(status, namespace, captures)
+
+		if IsNamespaceTerminating(namespace) {
+			message := fmt.Sprintf(
+				"Namespace is terminating since %s.",
+				namespace.DeletionTimestamp.UTC().Format(time.RFC3339),
+			)
+
+			SkipNamespace(status, namespace, TerminatingSkipReason, message)
+
+			if err := r.ProcessStuckNamespace(staleFeatureBranch, namespace); err != nil {
+				logger.Error(err, "An error occurred while inspect a stuck namespace.", "namespaceName", namespace.Name)
+				failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "inspect stuck", err))
+			}
+
+			continue
+		}
+
+		if IsNamespaceRescued(namespace) {
+			if err := r.RescueNamespace(&namespace, metav1.Now().Time); err != nil {
+				logger.Error(err, "An error occurred while rescue a namespace.", "namespaceName", namespace.Name)
+				failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "rescue", err))
+				continue
+			}
+
+			logger.Info("Namespace has been rescued from deletion.", "namespaceName", namespace.Name)
+
+			r.recordNamespaceEvent(
+				staleFeatureBranch,
+				&namespace,
+				corev1.EventTypeNormal,
+				RescuedEventReason,
+				"Namespace %s has been rescued from deletion.",
+				namespace.Name,
+			)
+		}
+
+		if IsNamespaceWakeRequested(namespace) {
+			if err := r.WakeNamespace(&namespace, metav1.Now().Time); err != nil {
+				logger.Error(err, "An error occurred while wake a namespace.", "namespaceName", namespace.Name)
+				failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "wake", err))
+				continue
+			}
+
+			logger.Info("Namespace has been woken.", "namespaceName", namespace.Name)
+
+			r.recordNamespaceEvent(
+				staleFeatureBranch,
+				&namespace,
+				corev1.EventTypeNormal,
+				WokenEventReason,
+				"Namespace %s has been woken.",
+				namespace.Name,
+			)
+		}
+
+		decision, err := r.IsNamespaceToBeDeleted(*staleFeatureBranch, matcher, namespace)
+
+		if err != nil {
+			logger.Error(err, "Unable to fetch the namespace's last deploy time.", "namespaceName", namespace.Name)
+			failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "fetch the last deploy time of", err))
+			continue
+		}
+
+		if !decision.IsToBeDeleted {
 			logger.Info(
-				"Namespace is being processing.",
+				"Namespace is skipped.",
 				"namespaceName", namespace.Name,
-				"namespaceCreationTimestamp", namespace.CreationTimestamp,
+				"reason", decision.SkipReason,
+				"message", decision.SkipMessage,
 			)
 
-			if err := r.Client.Delete(context.TODO(), &namespace); err != nil {
-				logger.Error(err, "An error occurred while delete a namespace.")
-				return reconcile.Result{}, err
+			SkipNamespace(status, namespace, decision.SkipReason, decision.SkipMessage)
+			SetNextStaleTime(status, decision.StaleTime)
+
+			if IsNamespaceMarked(namespace) {
+				if err := r.UnmarkNamespace(&namespace); err != nil {
+					logger.Error(err, "An error occurred while unmark a namespace.", "namespaceName", namespace.Name)
+					failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "unmark", err))
+					continue
+				}
+
+				logger.Info("Namespace has been unmarked for deletion.", "namespaceName", namespace.Name)
+
+				r.recordNamespaceEvent(
+					staleFeatureBranch,
+					&namespace,
+					corev1.EventTypeNormal,
+					UnmarkedEventReason,
+					"Namespace %s has been unmarked for deletion.",
+					namespace.Name,
+				)
 			}
 
-			logger.Info("Namespace has been deleted.", "namespaceName", namespace.Name)
+			continue
 		}
-	}
 
-	requeueIn, err := time.ParseDuration(strconv.Itoa(staleFeatureBranch.Spec.CheckEveryMinutes) + "m")
+		logger.Info(
+			"Namespace is being processing.",
+			"namespaceName", namespace.Name,
+			"namespaceCreationTimestamp", namespace.CreationTimestamp,
+			"captures", captures,
+		)
 
-	if err != nil {
-		logger.Error(err, "Unable to process check every minutes parameter.")
-		return reconcile.Result{}, nil
+		if staleFeatureBranch.Spec.Action == featurebranchv2.HibernateActionType {
+			isHibernated := IsNamespaceHibernated(namespace)
+			isToBeDeleted := false
+
+			if isHibernated {
+				isToBeDeleted, err = r.IsHibernatedNamespaceToBeDeleted(*staleFeatureBranch, namespace)
+
+				if err != nil {
+					logger.Error(err, "Unable to fetch the namespace's last deploy time.", "namespaceName", namespace.Name)
+					failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "fetch the last deploy time of", err))
+					continue
+				}
+			}
+
+			if !isHibernated && isDryRun {
+				logger.Info("Namespace would have been hibernated as dry run is enabled.", "namespaceName", namespace.Name)
+
+				r.recordNamespaceEvent(
+					staleFeatureBranch,
+					&namespace,
+					corev1.EventTypeNormal,
+					DryRunEventReason,
+					"Namespace %s would have been hibernated.%s",
+					namespace.Name,
+					formatEventCaptures(captures),
+				)
+
+				SkipNamespace(status, namespace, DryRunSkipReason, "Namespace would have been hibernated.")
+				continue
+			}
+
+			if !isHibernated {
+				if err := r.HibernateNamespace(&namespace, metav1.Now().Time); err != nil {
+					logger.Error(err, "An error occurred while hibernate a namespace.", "namespaceName", namespace.Name)
+					failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "hibernate", err))
+					continue
+				}
+
+				logger.Info("Namespace has been hibernated.", "namespaceName", namespace.Name)
+
+				r.recordNamespaceEvent(
+					staleFeatureBranch,
+					&namespace,
+					corev1.EventTypeNormal,
+					HibernatedEventReason,
+					"Namespace %s has been hibernated, annotate it with %s=true to wake it.",
+					namespace.Name,
+					featurebranch.WakeAnnotation,
+				)
+			}
+
+			if !isToBeDeleted {
+				status.HibernatedNamespaces = append(status.HibernatedNamespaces, namespace.Name)
+
+				SkipNamespace(
+					status,
+					namespace,
+					HibernatedSkipReason,
+					fmt.Sprintf("Namespace is hibernated at %s.", namespace.Annotations[featurebranch.HibernatedAtAnnotation]),
+				)
+				continue
+			}
+		}
+
+		if isDryRun {
+			logger.Info("Namespace would have been deleted as dry run is enabled.", "namespaceName", namespace.Name)
+
+			r.recordNamespaceEvent(
+				staleFeatureBranch,
+				&namespace,
+				corev1.EventTypeNormal,
+				DryRunEventReason,
+				"Namespace %s would have been deleted.%s",
+				namespace.Name,
+				formatEventCaptures(captures),
+			)
+
+			SkipNamespace(status, namespace, DryRunSkipReason, "Namespace would have been deleted.")
+			status.WouldBeDeletedNamespaces = append(status.WouldBeDeletedNamespaces, namespace.Name)
+			continue
+		}
+
+		if staleFeatureBranch.Spec.GracePeriod.Duration > 0 {
+			gracePeriod := staleFeatureBranch.Spec.GracePeriod.Duration
+			markTime, isMarked := GetNamespaceMarkTime(namespace)
+
+			if !isMarked {
+				markTime = metav1.Now().Time
+
+				if err := r.MarkNamespace(&namespace, markTime); err != nil {
+					logger.Error(err, "An error occurred while mark a namespace.", "namespaceName", namespace.Name)
+					failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "mark", err))
+					continue
+				}
+
+				logger.Info("Namespace has been marked for deletion.", "namespaceName", namespace.Name)
+
+				r.recordNamespaceEvent(
+					staleFeatureBranch,
+					&namespace,
+					corev1.EventTypeNormal,
+					MarkedForDeletionEventReason,
+					"Namespace %s has been marked for deletion, it will be deleted after %s unless %s annotation is removed.",
+					namespace.Name,
+					markTime.Add(gracePeriod).UTC().Format(time.RFC3339),
+					featurebranch.MarkedForDeletionAtAnnotation,
+				)
+
+				warnedNamespaces = append(
+					warnedNamespaces,
+					NewNotificationNamespace(staleFeatureBranch.Spec, namespace, markTime, gracePeriod),
+				)
+			}
+
+			deleteAfter := markTime.Add(gracePeriod)
+
+			if metav1.Now().Time.Before(deleteAfter) {
+				status.MarkedNamespaces = append(status.MarkedNamespaces, featurebranchv2.MarkedNamespace{
+					Name:        namespace.Name,
+					MarkedAt:    metav1.NewTime(markTime),
+					DeleteAfter: metav1.NewTime(deleteAfter),
+				})
+
+				SkipNamespace(
+					status,
+					namespace,
+					MarkedForDeletionSkipReason,
+					fmt.Sprintf("Namespace will be deleted after %s.", deleteAfter.UTC().Format(time.RFC3339)),
+				)
+				SetNextStaleTime(status, deleteAfter)
+				continue
+			}
+		}
+
+		isDeleted, err := r.IsNamespaceDeleted(namespace)
+
+		if err != nil {
+			logger.Error(err, "An error occurred while read a namespace.", "namespaceName", namespace.Name)
+			failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "read", err))
+			continue
+		}
+
+		if isDeleted {
+			SkipNamespace(status, namespace, TerminatingSkipReason, "Namespace is already being deleted.")
+			continue
+		}
+
+		deletionTime := metav1.Now().Time
+
+		if reason, message := r.ReserveDeletion(*staleFeatureBranch, deletionTime); reason != "" {
+			logger.Info("Deletion budget is exceeded, processing is stopped.", "namespaceName", namespace.Name, "reason", reason)
+
+			SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+				Type:    featurebranchv2.StaleFeatureBranchBudgetExceeded,
+				Status:  corev1.ConditionTrue,
+				Reason:  reason,
+				Message: message,
+			})
+
+			SkipNamespace(status, namespace, BudgetExceededSkipReason, message)
+
+			r.recordEvent(
+				staleFeatureBranch,
+				corev1.EventTypeWarning,
+				BudgetExceededEventReason,
+				"Deletion budget is exceeded, namespace %s and the rest aren't processed. %s",
+				namespace.Name,
+				message,
+			)
+
+			break
+		}
+
+		if staleFeatureBranch.Spec.Backup != nil {
+			location, err := r.BackupNamespace(*staleFeatureBranch, namespace)
+
+			if err != nil {
+				logger.Error(err, "An error occurred while back up a namespace.", "namespaceName", namespace.Name)
+
+				r.recordNamespaceEvent(
+					staleFeatureBranch,
+					&namespace,
+					corev1.EventTypeWarning,
+					BackupFailedEventReason,
+					"Unable to back up namespace %s, it isn't deleted: %v.",
+					namespace.Name,
+					err,
+				)
+
+				r.releaseDeletion(deletionTime)
+				failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "back up", err))
+				continue
+			}
+
+			status.BackedUpNamespaces = append(status.BackedUpNamespaces, featurebranchv2.BackedUpNamespace{
+				Name:     namespace.Name,
+				Location: location,
+			})
+
+			logger.Info("Namespace has been backed up.", "namespaceName", namespace.Name, "location", location)
+
+			r.recordNamespaceEvent(
+				staleFeatureBranch,
+				&namespace,
+				corev1.EventTypeNormal,
+				BackedUpEventReason,
+				"Namespace %s has been backed up to %s.",
+				namespace.Name,
+				location,
+			)
+		}
+
+		r.Recorder.Eventf(
+			&namespace,
+			corev1.EventTypeNormal,
+			DeletingEventReason,
+			"Namespace %s is being deleted by %s.",
+			namespace.Name,
+			DescribeStaleFeatureBranch(*staleFeatureBranch),
+		)
+
+		if err := r.Client.Delete(context.TODO(), &namespace); err != nil {
+			RecordDeleteFailureMetrics(*staleFeatureBranch)
+			logger.Error(err, "An error occurred while delete a namespace.")
+
+			r.recordNamespaceEvent(
+				staleFeatureBranch,
+				&namespace,
+				corev1.EventTypeWarning,
+				DeleteFailedEventReason,
+				"Unable to delete namespace %s: %v.",
+				namespace.Name,
+				err,
+			)
+
+			r.releaseDeletion(deletionTime)
+			failures = append(failures, FailNamespace(status, previousFailedNamespaces, namespace, "delete", err))
+			continue
+		}
+
+		status.DeletedNamespacesCount++
+		status.DeletedNamespaces = append(status.DeletedNamespaces, namespace.Name)
+		deletedNamespaces = append(deletedNamespaces, NewNotificationNamespace(staleFeatureBranch.Spec, namespace, metav1.Now().Time, 0))
+
+		logger.Info("Namespace has been deleted.", "namespaceName", namespace.Name)
+
+		r.recordEvent(
+			staleFeatureBranch,
+			corev1.EventTypeNormal,
+			DeletedEventReason,
+			"Namespace %s has been deleted.%s",
+			namespace.Name,
+			formatEventCaptures(captures),
+		)
 	}
 
-	return reconcile.Result{RequeueAfter: requeueIn}, nil
+	r.Notify(staleFeatureBranch, notification.WarningKind, warnedNamespaces)
+	r.Notify(staleFeatureBranch, notification.DeletedKind, deletedNamespaces)
+
+	return utilerrors.NewAggregate(failures)
+}
+
+// NamespaceDecision is a decision whether a feature branch's namespace is to be deleted and why it's skipped if not.
+// Stale time is the time the skipped namespace becomes stale if it's known.
+type NamespaceDecision struct {
+	IsToBeDeleted bool
+	SkipReason    string
+	SkipMessage   string
+	StaleTime     time.Time
 }
 
-func (r *ReconcileStaleFeatureBranch) IsNamespaceToBeDeleted(staleFeatureBranch featurebranchv1.StaleFeatureBranch, namespace corev1.Namespace) bool {
-	if !strings.Contains(namespace.Name, staleFeatureBranch.Spec.NamespaceSubstring) {
-		return false
+func (r *ReconcileStaleFeatureBranch) IsNamespaceToBeDeleted(staleFeatureBranch featurebranchv2.StaleFeatureBranch, matcher *NamespaceMatcher, namespace corev1.Namespace) (NamespaceDecision, error) {
+	if reason, message := GetNamespaceProtection(staleFeatureBranch, matcher, namespace); reason != "" {
+		return NamespaceDecision{SkipReason: reason, SkipMessage: message}, nil
 	}
 
 	if debugIsEnabled == os.Getenv("IS_DEBUG") {
@@ -83,16 +600,61 @@ func (r *ReconcileStaleFeatureBranch) IsNamespaceToBeDeleted(staleFeatureBranch
 			"Namespace should be deleted due to debug mode is enabled.",
 			"namespaceName", namespace.Name,
 		)
-		return true
+		return NamespaceDecision{IsToBeDeleted: true}, nil
+	}
+
+	expirationTime, isExpirable, err := GetNamespaceExpiration(namespace)
+
+	if err != nil {
+		return NamespaceDecision{
+			SkipReason: ExpiresAtAnnotationSkipReason,
+			SkipMessage: fmt.Sprintf(
+				"Namespace is annotated with %s, but its value is invalid: %v.", featurebranch.ExpiresAtAnnotation, err,
+			),
+		}, nil
+	}
+
+	if isExpirable {
+		if metav1.Now().Time.Before(expirationTime) {
+			return NamespaceDecision{
+				SkipReason:  ExpiresAtAnnotationSkipReason,
+				SkipMessage: fmt.Sprintf("Namespace expires at %s.", expirationTime.UTC().Format(time.RFC3339)),
+				StaleTime:   expirationTime,
+			}, nil
+		}
+
+		return NamespaceDecision{IsToBeDeleted: true}, nil
+	}
+
+	if staleFeatureBranch.Spec.PullRequest != nil {
+		reason, message, err := r.GetNamespacePullRequestSkip(staleFeatureBranch, namespace)
+
+		if err != nil {
+			return NamespaceDecision{}, err
+		}
+
+		if reason != "" {
+			return NamespaceDecision{SkipReason: reason, SkipMessage: message}, nil
+		}
+
+		return NamespaceDecision{IsToBeDeleted: true}, nil
+	}
+
+	lastActivity, err := r.GetNamespaceLastActivity(staleFeatureBranch.Spec, namespace)
+
+	if err != nil {
+		return NamespaceDecision{}, err
 	}
 
-	namespaceExistenceInHoursAsFloat := metav1.Now().Sub(namespace.CreationTimestamp.Time).Hours()
-	namespaceExistenceInHoursAsInteger := int(namespaceExistenceInHoursAsFloat)
-	afterHoursWithoutDeployAsInteger := staleFeatureBranch.Spec.AfterDaysWithoutDeploy * HoursInDay
+	withoutActivity := metav1.Now().Sub(lastActivity.Time)
 
-	if namespaceExistenceInHoursAsInteger >= afterHoursWithoutDeployAsInteger {
-		return true
+	if withoutActivity >= staleFeatureBranch.Spec.StaleAfter.Duration {
+		return NamespaceDecision{IsToBeDeleted: true}, nil
 	}
 
-	return false
+	return NamespaceDecision{
+		SkipReason:  NotStaleSkipReason,
+		SkipMessage: fmt.Sprintf("Namespace is last active %d hours ago by %s.", int(withoutActivity.Hours()), lastActivity.Reason),
+		StaleTime:   lastActivity.Time.Add(staleFeatureBranch.Spec.StaleAfter.Duration),
+	}, nil
 }
@@ -3,35 +3,40 @@ package stalefeaturebranch
 import (
 	"bou.ke/monkey"
 	"context"
+	"errors"
 	"os"
 	"testing"
 	"time"
 
-	featurebranchv1 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v1"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // Case: delete stale feature branches.
 // Where: some namespaces aren't feature branch namespaces.
-// Expected: namespaces match namespace substring and older that days without deployed are deleted.
+// Expected: namespaces match namespace substring and not deployed for stale after duration are deleted.
 func TestReconcilerStaleFeatureBranches(t *testing.T) {
 	// Set up data for tests.
 	var (
-		notFeatureBranchNamespaceName			 = "project"
-		staleFeatureBranchName                   = "stale-feature-branch-operator"
-		staleFeatureBranchNamespace              = "stale-feature-branch-operator"
-		staleFeatureBranchNamespaceSubstring     = "-pr-"
-		staleFeatureBranchAfterDaysWithoutDeploy = 1
-		staleFeatureBranchCheckEveryMinutes      = 1
-		oldNamespaceCreationTimestamp            = metav1.Date(
+		notFeatureBranchNamespaceName        = "project"
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		oldNamespaceCreationTimestamp        = metav1.Date(
 			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
 		)
 		currentTimestamp = metav1.Now()
@@ -43,15 +48,15 @@ func TestReconcilerStaleFeatureBranches(t *testing.T) {
 		t.Fatalf("An error occurred while enabling debug: (%v)", err)
 	}
 
-	staleFeatureBranch := &featurebranchv1.StaleFeatureBranch{
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      staleFeatureBranchName,
 			Namespace: staleFeatureBranchNamespace,
 		},
-		Spec: featurebranchv1.StaleFeatureBranchSpec{
-			NamespaceSubstring:     staleFeatureBranchNamespaceSubstring,
-			AfterDaysWithoutDeploy: staleFeatureBranchAfterDaysWithoutDeploy,
-			CheckEveryMinutes:      staleFeatureBranchCheckEveryMinutes,
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
 		},
 	}
 
@@ -93,11 +98,12 @@ func TestReconcilerStaleFeatureBranches(t *testing.T) {
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(featurebranchv1.SchemeGroupVersion, staleFeatureBranch)
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
 
 	reconciler = ReconcileStaleFeatureBranch{
-		Client: fake.NewFakeClientWithScheme(s, objects...),
-		Scheme: s,
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
 	}
 
 	request = reconcile.Request{
@@ -146,9 +152,9 @@ func TestReconcilerStaleFeatureBranches(t *testing.T) {
 
 	assert.Equal(
 		t,
-		float64(staleFeatureBranchCheckEveryMinutes),
-		res.RequeueAfter.Minutes(),
-		"Check every minutes parameter equals the reconcile's requeue after one.",
+		staleFeatureBranchInterval,
+		res.RequeueAfter,
+		"Interval equals the reconcile's requeue after one.",
 	)
 }
 
@@ -158,29 +164,29 @@ func TestReconcilerStaleFeatureBranches(t *testing.T) {
 func TestReconcilerNewStaleFeatureBranches(t *testing.T) {
 	// Set up data for tests.
 	var (
-		staleFeatureBranchName                   = "stale-feature-branch-operator"
-		staleFeatureBranchNamespace              = "stale-feature-branch-operator"
-		staleFeatureBranchNamespaceSubstring     = "-pr-"
-		staleFeatureBranchAfterDaysWithoutDeploy = 1
-		staleFeatureBranchCheckEveryMinutes      = 1
-		newNamespaceCreationTimestamp            = metav1.Now()
-		reconciler                               ReconcileStaleFeatureBranch
-		request                                  reconcile.Request
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		newNamespaceCreationTimestamp        = metav1.Now()
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
 	)
 
 	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
 		t.Fatalf("An error occurred while enabling debug: (%v)", err)
 	}
 
-	staleFeatureBranch := &featurebranchv1.StaleFeatureBranch{
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      staleFeatureBranchName,
 			Namespace: staleFeatureBranchNamespace,
 		},
-		Spec: featurebranchv1.StaleFeatureBranchSpec{
-			NamespaceSubstring:     staleFeatureBranchNamespaceSubstring,
-			AfterDaysWithoutDeploy: staleFeatureBranchAfterDaysWithoutDeploy,
-			CheckEveryMinutes:      staleFeatureBranchCheckEveryMinutes,
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
 		},
 	}
 
@@ -211,11 +217,12 @@ func TestReconcilerNewStaleFeatureBranches(t *testing.T) {
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(featurebranchv1.SchemeGroupVersion, staleFeatureBranch)
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
 
 	reconciler = ReconcileStaleFeatureBranch{
-		Client: fake.NewFakeClientWithScheme(s, objects...),
-		Scheme: s,
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
 	}
 
 	request = reconcile.Request{
@@ -247,25 +254,24 @@ func TestReconcilerNewStaleFeatureBranches(t *testing.T) {
 
 	assert.Equal(
 		t,
-		float64(staleFeatureBranchCheckEveryMinutes),
-		res.RequeueAfter.Minutes(),
-		"Check every minutes parameter equals the reconcile's requeue after one.",
+		staleFeatureBranchInterval,
+		res.RequeueAfter,
+		"Interval equals the reconcile's requeue after one.",
 	)
 }
 
-
 // Case: delete stale feature branch after 1 day (24 hours) without deploy.
 // Where: the only 23 hours and 59 minutes passed after its creation.
-// Expected: namespace isn't deleted.
+// Expected: namespace isn't deleted, the next run is made once it becomes stale, earlier than the interval.
 func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinutesLess(t *testing.T) {
 	// Set up data for tests.
 	var (
-		staleFeatureBranchName                   = "stale-feature-branch-operator"
-		staleFeatureBranchNamespace              = "stale-feature-branch-operator"
-		staleFeatureBranchNamespaceSubstring     = "-pr-"
-		staleFeatureBranchAfterDaysWithoutDeploy = 1
-		staleFeatureBranchCheckEveryMinutes      = 1
-		namespaceCreationTimestamp               = metav1.Date(
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		namespaceCreationTimestamp           = metav1.Date(
 			2010, time.January, 1, 0, 0, 0, 0, time.Local,
 		)
 		currentTimestamp = time.Date(
@@ -282,15 +288,15 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinutesLess(t *testing.T)
 		t.Fatalf("An error occurred while enabling debug: (%v)", err)
 	}
 
-	staleFeatureBranch := &featurebranchv1.StaleFeatureBranch{
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      staleFeatureBranchName,
 			Namespace: staleFeatureBranchNamespace,
 		},
-		Spec: featurebranchv1.StaleFeatureBranchSpec{
-			NamespaceSubstring:     staleFeatureBranchNamespaceSubstring,
-			AfterDaysWithoutDeploy: staleFeatureBranchAfterDaysWithoutDeploy,
-			CheckEveryMinutes:      staleFeatureBranchCheckEveryMinutes,
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
 		},
 	}
 
@@ -310,11 +316,12 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinutesLess(t *testing.T)
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(featurebranchv1.SchemeGroupVersion, staleFeatureBranch)
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
 
 	reconciler = ReconcileStaleFeatureBranch{
-		Client: fake.NewFakeClientWithScheme(s, objects...),
-		Scheme: s,
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
 	}
 
 	request = reconcile.Request{
@@ -346,9 +353,9 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinutesLess(t *testing.T)
 
 	assert.Equal(
 		t,
-		float64(staleFeatureBranchCheckEveryMinutes),
-		res.RequeueAfter.Minutes(),
-		"Check every minutes parameter equals the reconcile's requeue after one.",
+		namespaceCreationTimestamp.Add(staleFeatureBranchStaleAfter).Sub(currentTimestamp),
+		res.RequeueAfter,
+		"Reconcile is requeued until the namespace becomes stale.",
 	)
 }
 
@@ -358,12 +365,12 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinutesLess(t *testing.T)
 func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinuteMore(t *testing.T) {
 	// Set up data for tests.
 	var (
-		staleFeatureBranchName                   = "stale-feature-branch-operator"
-		staleFeatureBranchNamespace              = "stale-feature-branch-operator"
-		staleFeatureBranchNamespaceSubstring     = "-pr-"
-		staleFeatureBranchAfterDaysWithoutDeploy = 1
-		staleFeatureBranchCheckEveryMinutes      = 1
-		namespaceCreationTimestamp               = metav1.Date(
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		namespaceCreationTimestamp           = metav1.Date(
 			2010, time.January, 1, 0, 0, 0, 0, time.Local,
 		)
 		currentTimestamp = time.Date(
@@ -380,15 +387,15 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinuteMore(t *testing.T) {
 		t.Fatalf("An error occurred while enabling debug: (%v)", err)
 	}
 
-	staleFeatureBranch := &featurebranchv1.StaleFeatureBranch{
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      staleFeatureBranchName,
 			Namespace: staleFeatureBranchNamespace,
 		},
-		Spec: featurebranchv1.StaleFeatureBranchSpec{
-			NamespaceSubstring:     staleFeatureBranchNamespaceSubstring,
-			AfterDaysWithoutDeploy: staleFeatureBranchAfterDaysWithoutDeploy,
-			CheckEveryMinutes:      staleFeatureBranchCheckEveryMinutes,
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
 		},
 	}
 
@@ -408,11 +415,12 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinuteMore(t *testing.T) {
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(featurebranchv1.SchemeGroupVersion, staleFeatureBranch)
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
 
 	reconciler = ReconcileStaleFeatureBranch{
-		Client: fake.NewFakeClientWithScheme(s, objects...),
-		Scheme: s,
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
 	}
 
 	request = reconcile.Request{
@@ -444,9 +452,9 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinuteMore(t *testing.T) {
 
 	assert.Equal(
 		t,
-		float64(staleFeatureBranchCheckEveryMinutes),
-		res.RequeueAfter.Minutes(),
-		"Check every minutes parameter equals the reconcile's requeue after one.",
+		staleFeatureBranchInterval,
+		res.RequeueAfter,
+		"Interval equals the reconcile's requeue after one.",
 	)
 }
 
@@ -456,12 +464,12 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeOneMinuteMore(t *testing.T) {
 func TestReconcilerStaleFeatureBranchesDeletionTimeExactAfterDayesWithoutDeploy(t *testing.T) {
 	// Set up data for tests.
 	var (
-		staleFeatureBranchName                   = "stale-feature-branch-operator"
-		staleFeatureBranchNamespace              = "stale-feature-branch-operator"
-		staleFeatureBranchNamespaceSubstring     = "-pr-"
-		staleFeatureBranchAfterDaysWithoutDeploy = 1
-		staleFeatureBranchCheckEveryMinutes      = 1
-		namespaceCreationTimestamp               = metav1.Date(
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		namespaceCreationTimestamp           = metav1.Date(
 			2010, time.January, 1, 0, 0, 0, 0, time.Local,
 		)
 		currentTimestamp = time.Date(
@@ -478,15 +486,15 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeExactAfterDayesWithoutDeploy(
 		t.Fatalf("An error occurred while enabling debug: (%v)", err)
 	}
 
-	staleFeatureBranch := &featurebranchv1.StaleFeatureBranch{
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      staleFeatureBranchName,
 			Namespace: staleFeatureBranchNamespace,
 		},
-		Spec: featurebranchv1.StaleFeatureBranchSpec{
-			NamespaceSubstring:     staleFeatureBranchNamespaceSubstring,
-			AfterDaysWithoutDeploy: staleFeatureBranchAfterDaysWithoutDeploy,
-			CheckEveryMinutes:      staleFeatureBranchCheckEveryMinutes,
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
 		},
 	}
 
@@ -506,11 +514,12 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeExactAfterDayesWithoutDeploy(
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(featurebranchv1.SchemeGroupVersion, staleFeatureBranch)
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
 
 	reconciler = ReconcileStaleFeatureBranch{
-		Client: fake.NewFakeClientWithScheme(s, objects...),
-		Scheme: s,
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
 	}
 
 	request = reconcile.Request{
@@ -542,42 +551,41 @@ func TestReconcilerStaleFeatureBranchesDeletionTimeExactAfterDayesWithoutDeploy(
 
 	assert.Equal(
 		t,
-		float64(staleFeatureBranchCheckEveryMinutes),
-		res.RequeueAfter.Minutes(),
-		"Check every minutes parameter equals the reconcile's requeue after one.",
+		staleFeatureBranchInterval,
+		res.RequeueAfter,
+		"Interval equals the reconcile's requeue after one.",
 	)
 }
 
-
 // Case: delete new stale feature branches.
 // Where: debug is enabled.
 // Expected: new namespaces are deleted.
 func TestReconcilerStaleFeatureBranchesDebugEnabled(t *testing.T) {
 	// Set up data for tests.
 	var (
-		staleFeatureBranchName                   = "stale-feature-branch-operator"
-		staleFeatureBranchNamespace              = "stale-feature-branch-operator"
-		staleFeatureBranchNamespaceSubstring     = "-pr-"
-		staleFeatureBranchAfterDaysWithoutDeploy = 1
-		staleFeatureBranchCheckEveryMinutes      = 1
-		newNamespaceCreationTimestamp            = metav1.Now()
-		reconciler                               ReconcileStaleFeatureBranch
-		request                                  reconcile.Request
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		newNamespaceCreationTimestamp        = metav1.Now()
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
 	)
 
 	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
 		t.Fatalf("An error occurred while enabling debug: (%v)", err)
 	}
 
-	staleFeatureBranch := &featurebranchv1.StaleFeatureBranch{
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      staleFeatureBranchName,
 			Namespace: staleFeatureBranchNamespace,
 		},
-		Spec: featurebranchv1.StaleFeatureBranchSpec{
-			NamespaceSubstring:     staleFeatureBranchNamespaceSubstring,
-			AfterDaysWithoutDeploy: staleFeatureBranchAfterDaysWithoutDeploy,
-			CheckEveryMinutes:      staleFeatureBranchCheckEveryMinutes,
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
 		},
 	}
 
@@ -608,11 +616,12 @@ func TestReconcilerStaleFeatureBranchesDebugEnabled(t *testing.T) {
 	}
 
 	s := scheme.Scheme
-	s.AddKnownTypes(featurebranchv1.SchemeGroupVersion, staleFeatureBranch)
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
 
 	reconciler = ReconcileStaleFeatureBranch{
-		Client: fake.NewFakeClientWithScheme(s, objects...),
-		Scheme: s,
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
 	}
 
 	request = reconcile.Request{
@@ -644,8 +653,1432 @@ func TestReconcilerStaleFeatureBranchesDebugEnabled(t *testing.T) {
 
 	assert.Equal(
 		t,
-		float64(staleFeatureBranchCheckEveryMinutes),
-		res.RequeueAfter.Minutes(),
-		"Check every minutes parameter equals the reconcile's requeue after one.",
+		staleFeatureBranchInterval,
+		res.RequeueAfter,
+		"Interval equals the reconcile's requeue after one.",
+	)
+}
+
+// Case: delete stale feature branch after 1 day (24 hours) without deploy.
+// Where: namespace is created long time ago, but its deployment is rolled out an hour ago.
+// Expected: namespace isn't deleted.
+func TestReconcilerStaleFeatureBranchesRecentDeploy(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		namespaceCreationTimestamp           = metav1.Date(
+			2010, time.January, 1, 0, 0, 0, 0, time.Local,
+		)
+		deploymentRolloutTimestamp = metav1.Date(
+			2010, time.January, 10, 0, 0, 0, 0, time.Local,
+		)
+		currentTimestamp = time.Date(
+			2010, time.January, 10, 1, 0, 0, 0, time.Local,
+		)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
+	)
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			Namespace:         "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{
+					Type:           appsv1.DeploymentProgressing,
+					Status:         corev1.ConditionTrue,
+					LastUpdateTime: deploymentRolloutTimestamp,
+				},
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		namespace,
+		deployment,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		1,
+		len(allNamespaces.Items),
+		"Namespace isn't deleted as its deployment is rolled out recently.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: dry run is enabled in the stale feature branch's specifications.
+// Expected: namespaces aren't deleted, but recorded to status and events.
+func TestReconcilerStaleFeatureBranchesDryRun(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		oldNamespaceCreationTimestamp        = metav1.Date(
+			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
+		)
+		recorder   = record.NewFakeRecorder(100)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+			DryRun:             true,
+		},
+	}
+
+	firstFeatureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	secondFeatureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		firstFeatureBranchNamespace,
+		secondFeatureBranchNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: recorder,
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		2,
+		len(allNamespaces.Items),
+		"As dry run is enabled, namespaces aren't deleted.",
+	)
+
+	var processedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &processedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		[]string{"project-pr-1", "project-pr-2"},
+		processedStaleFeatureBranch.Status.WouldBeDeletedNamespaces,
+		"Namespaces that would have been deleted are recorded to the status.",
+	)
+
+	assert.Contains(
+		t,
+		receiveEvents(recorder),
+		"Normal DryRun Namespace project-pr-1 would have been deleted.",
+		"Namespace that would have been deleted is recorded to events.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: dry run is enabled for the whole operator.
+// Expected: namespaces aren't deleted.
+func TestReconcilerStaleFeatureBranchesOperatorDryRun(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		oldNamespaceCreationTimestamp        = metav1.Date(
+			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
+		)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	if err := os.Setenv("IS_DRY_RUN", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling dry run: (%v)", err)
+	}
+
+	defer os.Unsetenv("IS_DRY_RUN")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		namespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		1,
+		len(allNamespaces.Items),
+		"As dry run is enabled for the operator, namespace isn't deleted.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: one namespace is stale, one is new, one isn't a feature branch namespace.
+// Expected: the run's results are recorded to the stale feature branch's status.
+func TestReconcilerStaleFeatureBranchesStatus(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName                     = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace                = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring       = "-pr-"
+		staleFeatureBranchStaleAfter               = 24 * time.Hour
+		staleFeatureBranchInterval                 = time.Minute
+		staleFeatureBranchGeneration         int64 = 3
+		oldNamespaceCreationTimestamp              = metav1.Date(
+			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
+		)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
 	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       staleFeatureBranchName,
+			Namespace:  staleFeatureBranchNamespace,
+			Generation: staleFeatureBranchGeneration,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	staleNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	newNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: metav1.Now(),
+		},
+	}
+
+	notFeatureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		staleNamespace,
+		newNamespace,
+		notFeatureBranchNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var processedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &processedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	status := processedStaleFeatureBranch.Status
+
+	assert.Equal(t, 2, status.MatchedNamespacesCount, "2 from 3 namespaces have namespace substring.")
+	assert.Equal(t, 1, status.DeletedNamespacesCount, "The only stale namespace is deleted.")
+	assert.Equal(t, 1, status.SkippedNamespacesCount, "The only new namespace is skipped.")
+	assert.Equal(t, []string{"project-pr-1"}, status.DeletedNamespaces, "Deleted namespace is recorded.")
+	assert.Equal(t, staleFeatureBranchGeneration, status.ObservedGeneration, "Generation is observed.")
+	assert.Empty(t, status.LastError, "There is no error as the run succeeded.")
+
+	if assert.NotNil(t, status.LastRunTime) && assert.NotNil(t, status.NextRunTime) {
+		assert.Equal(
+			t,
+			staleFeatureBranchInterval,
+			status.NextRunTime.Sub(status.LastRunTime.Time),
+			"Next run is scheduled in the interval after the last run.",
+		)
+	}
+
+	readyCondition := GetCondition(status, featurebranchv2.StaleFeatureBranchReady)
+
+	if assert.NotNil(t, readyCondition) {
+		assert.Equal(t, corev1.ConditionTrue, readyCondition.Status, "Stale feature branch is ready.")
+	}
+
+	degradedCondition := GetCondition(status, featurebranchv2.StaleFeatureBranchDegraded)
+
+	if assert.NotNil(t, degradedCondition) {
+		assert.Equal(t, corev1.ConditionFalse, degradedCondition.Status, "Stale feature branch isn't degraded.")
+	}
+}
+
+// Case: delete stale feature branches.
+// Where: namespaces are matched by a regular expression with capture groups.
+// Expected: only namespaces matching the whole pattern are deleted, capture groups are recorded to status.
+func TestReconcilerStaleFeatureBranchesNamespacePattern(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName             = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace        = "stale-feature-branch-operator"
+		staleFeatureBranchNamespacePattern = `(?P<project>[a-z-]+)-pr-(?P<number>\d+)`
+		staleFeatureBranchStaleAfter       = 24 * time.Hour
+		staleFeatureBranchInterval         = time.Minute
+		oldNamespaceCreationTimestamp      = metav1.Date(
+			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
+		)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespacePattern:     staleFeatureBranchNamespacePattern,
+			NamespacePatternType: featurebranchv2.RegexpNamespacePatternType,
+			StaleAfter:           metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:             metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	featureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-17",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	toolsNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "ops-pr-tools",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		featureBranchNamespace,
+		toolsNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, 1, len(allNamespaces.Items), "The only namespace matching the pattern is deleted.")
+	assert.Equal(t, "ops-pr-tools", allNamespaces.Items[0].Name, "Namespace not matching the pattern is kept.")
+
+	var processedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &processedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		[]featurebranchv2.MatchedNamespace{
+			{
+				Name:     "project-pr-17",
+				Captures: map[string]string{"project": "project", "number": "17"},
+			},
+		},
+		processedStaleFeatureBranch.Status.MatchedNamespaces,
+		"Capture groups of the matched namespace are recorded to the status.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: namespace pattern is an invalid regular expression.
+// Expected: namespaces aren't deleted, the stale feature branch is degraded.
+func TestReconcilerStaleFeatureBranchesInvalidNamespacePattern(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName        = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace   = "stale-feature-branch-operator"
+		staleFeatureBranchStaleAfter  = 24 * time.Hour
+		oldNamespaceCreationTimestamp = metav1.Date(
+			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
+		)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
+	)
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespacePattern: "project-pr-(",
+			StaleAfter:       metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+		},
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		namespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, 1, len(allNamespaces.Items), "Namespace isn't deleted as the pattern is invalid.")
+
+	var processedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &processedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	degradedCondition := GetCondition(processedStaleFeatureBranch.Status, featurebranchv2.StaleFeatureBranchDegraded)
+
+	if assert.NotNil(t, degradedCondition) {
+		assert.Equal(t, corev1.ConditionTrue, degradedCondition.Status, "Stale feature branch is degraded.")
+		assert.Equal(
+			t,
+			InvalidSpecificationsConditionReason,
+			degradedCondition.Reason,
+			"Stale feature branch is degraded due to invalid specifications.",
+		)
+	}
+}
+
+// Case: delete stale feature branches.
+// Where: namespaces are selected by labels only.
+// Expected: the only namespace with matching labels is deleted.
+func TestReconcilerStaleFeatureBranchesNamespaceSelector(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName        = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace   = "stale-feature-branch-operator"
+		staleFeatureBranchStaleAfter  = 24 * time.Hour
+		staleFeatureBranchInterval    = time.Minute
+		oldNamespaceCreationTimestamp = metav1.Date(
+			2010, time.November, 10, 10, 10, 10, 10, time.UTC,
+		)
+		reconciler ReconcileStaleFeatureBranch
+		request    reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"preview": "true", "app": "project"},
+			},
+			StaleAfter: metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:   metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	previewNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-17",
+			Labels:            map[string]string{"preview": "true", "app": "project"},
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	anotherProjectPreviewNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "another-project-17",
+			Labels:            map[string]string{"preview": "true", "app": "another-project"},
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	productionNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project",
+			Labels:            map[string]string{"app": "project"},
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		previewNamespace,
+		anotherProjectPreviewNamespace,
+		productionNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	var namespacesNames []string
+
+	for _, namespace := range allNamespaces.Items {
+		namespacesNames = append(namespacesNames, namespace.Name)
+	}
+
+	assert.ElementsMatch(
+		t,
+		[]string{"another-project-17", "project"},
+		namespacesNames,
+		"The only namespace with all the selector's labels is deleted.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: namespace substring matches system namespaces, debug is enabled.
+// Expected: system namespaces aren't deleted, skip reasons are recorded to status.
+func TestReconcilerStaleFeatureBranchesProtectedNamespaces(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	systemNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+		},
+	}
+
+	operatorNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: staleFeatureBranchNamespace,
+		},
+	}
+
+	featureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-pr-1",
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		systemNamespace,
+		operatorNamespace,
+		featureBranchNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, 2, len(allNamespaces.Items), "The only feature branch's namespace is deleted.")
+
+	var processedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &processedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	status := processedStaleFeatureBranch.Status
+
+	assert.Equal(t, 2, status.SkippedNamespacesCount, "Protected namespaces are skipped.")
+
+	for _, skippedNamespace := range status.SkippedNamespaces {
+		assert.Equal(t, ProtectedSkipReason, skippedNamespace.Reason, "Namespace is skipped as it's protected.")
+	}
+}
+
+// Case: delete stale feature branches.
+// Where: schedule is at 02:00 daily, the last run is done today at 02:00, the operator is restarted at 12:00.
+// Expected: namespaces aren't deleted, the reconcile is requeued to the next day at 02:00.
+func TestReconcilerStaleFeatureBranchesScheduleNotDue(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchSchedule           = "0 2 * * *"
+		lastRunTime                          = metav1.Date(2010, time.January, 10, 2, 0, 0, 0, time.UTC)
+		currentTimestamp                     = time.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Schedule:           staleFeatureBranchSchedule,
+		},
+		Status: featurebranchv2.StaleFeatureBranchStatus{
+			LastRunTime: &lastRunTime,
+		},
+	}
+
+	featureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-pr-1",
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		featureBranchNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	res, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Equal(t, 1, len(allNamespaces.Items), "Namespace isn't deleted as the run isn't due yet.")
+	assert.Equal(t, 14*time.Hour, res.RequeueAfter, "Reconcile is requeued to the next day at 02:00.")
+}
+
+// Case: delete stale feature branches.
+// Where: grace period is specified, namespaces are unmarked, marked long ago, marked recently, rescued and deployed.
+// Expected: unmarked stale namespace is marked, namespace marked long ago is deleted, namespace marked recently is
+// kept, rescued namespace is kept and stamped, deployed namespace is unmarked.
+func TestReconcilerStaleFeatureBranchesGracePeriod(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		staleFeatureBranchGracePeriod        = time.Hour
+		oldNamespaceCreationTimestamp        = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		currentTimestamp                     = time.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while disabling debug: (%v)", err)
+	}
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+			GracePeriod:        metav1.Duration{Duration: staleFeatureBranchGracePeriod},
+		},
+	}
+
+	unmarkedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	markedLongAgoNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+			Annotations: map[string]string{
+				featurebranch.MarkedForDeletionAtAnnotation: "2010-01-10T10:00:00Z",
+			},
+			Labels: map[string]string{
+				featurebranch.MarkedForDeletionLabel: "true",
+			},
+		},
+	}
+
+	markedRecentlyNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-3",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+			Annotations: map[string]string{
+				featurebranch.MarkedForDeletionAtAnnotation: "2010-01-10T11:50:00Z",
+			},
+			Labels: map[string]string{
+				featurebranch.MarkedForDeletionLabel: "true",
+			},
+		},
+	}
+
+	rescuedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-4",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+			Labels: map[string]string{
+				featurebranch.MarkedForDeletionLabel: "true",
+			},
+		},
+	}
+
+	deployedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-5",
+			CreationTimestamp: metav1.NewTime(currentTimestamp),
+			Annotations: map[string]string{
+				featurebranch.MarkedForDeletionAtAnnotation: "2010-01-10T11:50:00Z",
+			},
+			Labels: map[string]string{
+				featurebranch.MarkedForDeletionLabel: "true",
+			},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		unmarkedNamespace,
+		markedLongAgoNamespace,
+		markedRecentlyNamespace,
+		rescuedNamespace,
+		deployedNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	namespaces := make(map[string]corev1.Namespace)
+
+	for _, namespace := range allNamespaces.Items {
+		namespaces[namespace.Name] = namespace
+	}
+
+	assert.Equal(t, 4, len(namespaces), "The only namespace marked long ago is deleted.")
+	assert.NotContains(t, namespaces, "project-pr-2", "Namespace marked long ago is deleted.")
+
+	assert.Equal(
+		t,
+		"2010-01-10T12:00:00Z",
+		namespaces["project-pr-1"].Annotations[featurebranch.MarkedForDeletionAtAnnotation],
+		"Unmarked stale namespace is marked for deletion.",
+	)
+	assert.Equal(
+		t,
+		"true",
+		namespaces["project-pr-1"].Labels[featurebranch.MarkedForDeletionLabel],
+		"Unmarked stale namespace is labeled as marked for deletion.",
+	)
+
+	assert.Equal(
+		t,
+		"2010-01-10T11:50:00Z",
+		namespaces["project-pr-3"].Annotations[featurebranch.MarkedForDeletionAtAnnotation],
+		"Namespace marked recently keeps its mark.",
+	)
+
+	assert.Equal(
+		t,
+		"2010-01-10T12:00:00Z",
+		namespaces["project-pr-4"].Annotations[featurebranch.RescuedAtAnnotation],
+		"Rescued namespace is stamped with the rescue time.",
+	)
+	assert.NotContains(
+		t,
+		namespaces["project-pr-4"].Labels,
+		featurebranch.MarkedForDeletionLabel,
+		"Rescued namespace isn't labeled as marked for deletion.",
+	)
+
+	assert.False(t, IsNamespaceMarked(namespaces["project-pr-5"]), "Deployed namespace is unmarked.")
+
+	var processedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &processedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching the stale feature branch: (%v)", err)
+	}
+
+	status := processedStaleFeatureBranch.Status
+
+	assert.Equal(t, []string{"project-pr-2"}, status.DeletedNamespaces, "Namespace marked long ago is deleted.")
+	assert.Equal(t, 2, len(status.MarkedNamespaces), "Marked namespaces are recorded to the status.")
+	assert.True(
+		t,
+		time.Date(2010, time.January, 10, 13, 0, 0, 0, time.UTC).Equal(status.MarkedNamespaces[0].DeleteAfter.Time),
+		"Namespace marked right now is deleted after the grace period.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: one namespace is stale, another one is deployed recently.
+// Expected: only deleting and deleted actions are recorded to events, matched and skipped namespaces aren't.
+func TestReconcilerStaleFeatureBranchesEvents(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		oldNamespaceCreationTimestamp        = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		recorder                             = record.NewFakeRecorder(100)
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "false"); err != nil {
+		t.Fatalf("An error occurred while disabling debug: (%v)", err)
+	}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	staleNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: oldNamespaceCreationTimestamp,
+		},
+	}
+
+	deployedNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: metav1.Now(),
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		staleNamespace,
+		deployedNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: recorder,
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		[]string{
+			"Normal Deleting Namespace project-pr-1 is being deleted by stale feature branch " +
+				"stale-feature-branch-operator/stale-feature-branch-operator.",
+			"Normal Deleted Namespace project-pr-1 has been deleted.",
+		},
+		receiveEvents(recorder),
+		"Only actions taken are recorded to events.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: the namespace deletion fails.
+// Expected: failure is recorded to warning events of the stale feature branch and the namespace.
+func TestReconcilerStaleFeatureBranchesDeleteFailedEvents(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName               = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace          = "stale-feature-branch-operator"
+		staleFeatureBranchNamespaceSubstring = "-pr-"
+		staleFeatureBranchStaleAfter         = 24 * time.Hour
+		staleFeatureBranchInterval           = time.Minute
+		recorder                             = record.NewFakeRecorder(100)
+		reconciler                           ReconcileStaleFeatureBranch
+		request                              reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: staleFeatureBranchNamespaceSubstring,
+			StaleAfter:         metav1.Duration{Duration: staleFeatureBranchStaleAfter},
+			Interval:           metav1.Duration{Duration: staleFeatureBranchInterval},
+		},
+	}
+
+	featureBranchNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "project-pr-1",
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		featureBranchNamespace,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client: failingDeleteClient{
+			Client:           fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+			failedNamespaces: map[string]bool{"project-pr-1": true},
+		},
+		Scheme:   s,
+		Recorder: recorder,
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	assert.Error(t, err, "Failed deletion is returned as an error.")
+
+	events := receiveEvents(recorder)
+
+	assert.Contains(
+		t,
+		events,
+		"Warning DeleteFailed Unable to delete namespace project-pr-1: namespace deletion is forbidden.",
+		"Failed deletion is recorded to events.",
+	)
+	assert.Contains(
+		t,
+		events,
+		"Warning ProcessingFailed Processing of feature branches' namespaces failed: "+
+			"unable to delete namespace project-pr-1: namespace deletion is forbidden.",
+		"Failed processing is recorded to events.",
+	)
+}
+
+// Case: delete stale feature branches.
+// Where: deletion of the first namespace fails.
+// Expected: the rest of namespaces are deleted, failed namespace is recorded to the status and returned as an error.
+func TestReconcilerStaleFeatureBranchesContinueAfterDeleteFailure(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace = "stale-feature-branch-operator"
+		reconciler                  ReconcileStaleFeatureBranch
+		request                     reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Interval:           metav1.Duration{Duration: 30 * time.Minute},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-2"}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client: failingDeleteClient{
+			Client:           fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+			failedNamespaces: map[string]bool{"project-pr-1": true},
+		},
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	assert.EqualError(
+		t,
+		err,
+		"unable to delete namespace project-pr-1: namespace deletion is forbidden",
+		"Failed deletion is returned as an error.",
+	)
+
+	var updatedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &updatedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching a stale feature branch: (%v)", err)
+	}
+
+	status := updatedStaleFeatureBranch.Status
+
+	assert.Equal(t, []string{"project-pr-2"}, status.DeletedNamespaces, "The rest of namespaces are deleted.")
+	assert.Equal(t, 1, len(status.FailedNamespaces), "Failed namespace is recorded to the status.")
+	assert.Equal(t, "project-pr-1", status.FailedNamespaces[0].Name, "Failed namespace's name is recorded.")
+	assert.Equal(t, 1, status.FailedNamespaces[0].Failures, "Failed namespace's failures are counted.")
+	assert.True(
+		t,
+		status.NextRunTime.Time.Equal(status.FailedNamespaces[0].RetryAfter.Time),
+		"Failed namespace is retried before the scheduled run.",
+	)
+}
+
+// failingDeleteClient fails deletion of the namespaces.
+type failingDeleteClient struct {
+	client.Client
+	failedNamespaces map[string]bool
+}
+
+func (c failingDeleteClient) Delete(ctx context.Context, object runtime.Object, opts ...client.DeleteOption) error {
+	if namespace, isNamespace := object.(*corev1.Namespace); isNamespace && c.failedNamespaces[namespace.Name] {
+		return errors.New("namespace deletion is forbidden")
+	}
+
+	return c.Client.Delete(ctx, object, opts...)
+}
+
+// receiveEvents receives all events recorded so far.
+func receiveEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+// ownNamespaces labels the namespaces among the objects as owned by the namespace of stale feature branches.
+func ownNamespaces(owner string, objects []runtime.Object) []runtime.Object {
+	for _, object := range objects {
+		if namespace, isNamespace := object.(*corev1.Namespace); isNamespace {
+			if namespace.Labels == nil {
+				namespace.Labels = make(map[string]string)
+			}
+
+			namespace.Labels[featurebranch.OwnerLabel] = owner
+		}
+	}
+
+	return objects
 }
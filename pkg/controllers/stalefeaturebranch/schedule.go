@@ -0,0 +1,120 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/robfig/cron/v3"
+)
+
+// RunSchedule computes times of stale feature branch's runs either by a cron schedule in a time zone or by a fixed
+// number of minutes between runs.
+type RunSchedule struct {
+	cronSchedule cron.Schedule
+	interval     time.Duration
+}
+
+// NewRunSchedule creates the run schedule from the stale feature branch's specifications. Cron schedule takes
+// precedence over the interval. Time zone is UTC if not specified.
+func NewRunSchedule(spec featurebranchv2.StaleFeatureBranchSpec) (*RunSchedule, error) {
+	if spec.Schedule == "" {
+		if spec.TimeZone != "" {
+			return nil, fmt.Errorf("time zone %q is specified without a schedule", spec.TimeZone)
+		}
+
+		if spec.Interval.Duration <= 0 {
+			return nil, fmt.Errorf("interval should be positive, got %s", spec.Interval.Duration)
+		}
+
+		return &RunSchedule{interval: spec.Interval.Duration}, nil
+	}
+
+	location := time.UTC
+
+	if spec.TimeZone != "" {
+		timeZoneLocation, err := time.LoadLocation(spec.TimeZone)
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", spec.TimeZone, err)
+		}
+
+		location = timeZoneLocation
+	}
+
+	cronSchedule, err := cron.ParseStandard(spec.Schedule)
+
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", spec.Schedule, err)
+	}
+
+	if specSchedule, isSpecSchedule := cronSchedule.(*cron.SpecSchedule); isSpecSchedule {
+		specSchedule.Location = location
+	}
+
+	return &RunSchedule{cronSchedule: cronSchedule}, nil
+}
+
+// Next returns the first run's time after the given time.
+func (s *RunSchedule) Next(after time.Time) time.Time {
+	if s.cronSchedule != nil {
+		return s.cronSchedule.Next(after)
+	}
+
+	return after.Add(s.interval)
+}
+
+// NextAtOrAfter returns the first run's time at or after the given time. Cron schedule moves the time forward to its
+// next tick, so runs are never made outside of the schedule's window.
+func (s *RunSchedule) NextAtOrAfter(at time.Time) time.Time {
+	if s.cronSchedule != nil {
+		return s.cronSchedule.Next(at.Add(-time.Nanosecond))
+	}
+
+	return at
+}
+
+// GetNextRunTime returns the time of the first run after the given time. Run is made earlier than scheduled to retry
+// the namespaces failed during the last run or once the first of skipped namespaces becomes stale (both at the cron
+// schedule's next tick if the schedule is set).
+func GetNextRunTime(status featurebranchv2.StaleFeatureBranchStatus, schedule *RunSchedule, after time.Time) time.Time {
+	nextRunTime := schedule.Next(after)
+
+	if status.NextStaleTime != nil {
+		staleRunTime := schedule.NextAtOrAfter(status.NextStaleTime.Time)
+
+		if staleRunTime.Before(nextRunTime) {
+			nextRunTime = staleRunTime
+		}
+	}
+
+	for _, failedNamespace := range status.FailedNamespaces {
+		retryRunTime := schedule.NextAtOrAfter(failedNamespace.RetryAfter.Time)
+
+		if retryRunTime.Before(nextRunTime) {
+			nextRunTime = retryRunTime
+		}
+	}
+
+	return nextRunTime
+}
+
+// GetDueRunTime returns the time of the stale feature branch's next run and whether the run is due at the given time.
+// The run is due right away if the stale feature branch has never run or its specifications are changed since the
+// last run. If several runs are missed (e.g. the operator was down), they are collapsed into a single run.
+func GetDueRunTime(staleFeatureBranch featurebranchv2.StaleFeatureBranch, schedule *RunSchedule, now time.Time) (time.Time, bool) {
+	status := staleFeatureBranch.Status
+
+	if status.LastRunTime == nil || status.ObservedGeneration != staleFeatureBranch.Generation {
+		return now, true
+	}
+
+	nextRunTime := GetNextRunTime(status, schedule, status.LastRunTime.Time)
+
+	if now.Before(nextRunTime) {
+		return nextRunTime, false
+	}
+
+	return now, true
+}
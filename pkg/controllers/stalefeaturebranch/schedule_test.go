@@ -0,0 +1,282 @@
+package stalefeaturebranch
+
+import (
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: create a run schedule.
+// Where: schedule is at 02:00 on weekdays in a time zone.
+// Expected: next run is at 02:00 of the next weekday in the time zone.
+func TestNewRunScheduleTimeZone(t *testing.T) {
+	// Set up data for tests.
+	location, err := time.LoadLocation("Europe/Kiev")
+
+	if err != nil {
+		t.Fatalf("An error occurred while loading a time zone: (%v)", err)
+	}
+
+	spec := featurebranchv2.StaleFeatureBranchSpec{
+		Schedule: "0 2 * * 1-5",
+		TimeZone: "Europe/Kiev",
+	}
+
+	// Friday.
+	after := time.Date(2010, time.January, 8, 12, 0, 0, 0, location)
+
+	// Testing.
+	schedule, err := NewRunSchedule(spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	expectedNextRunTime := time.Date(2010, time.January, 11, 2, 0, 0, 0, location)
+
+	assert.True(t, expectedNextRunTime.Equal(schedule.Next(after)), "Next run is on Monday at 02:00.")
+}
+
+// Case: create a run schedule.
+// Where: schedule isn't specified.
+// Expected: next run is after the interval.
+func TestNewRunScheduleInterval(t *testing.T) {
+	// Set up data for tests.
+	spec := featurebranchv2.StaleFeatureBranchSpec{
+		Interval: metav1.Duration{Duration: 30 * time.Minute},
+	}
+
+	after := time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+	// Testing.
+	schedule, err := NewRunSchedule(spec)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	assert.Equal(t, after.Add(30*time.Minute), schedule.Next(after), "Next run is in 30 minutes.")
+}
+
+// Case: create a run schedule.
+// Where: schedule or time zone are invalid.
+// Expected: error is returned.
+func TestNewRunScheduleInvalid(t *testing.T) {
+	// Set up data for tests.
+	specs := []featurebranchv2.StaleFeatureBranchSpec{
+		{Schedule: "0 2 * *"},
+		{Schedule: "0 2 * * *", TimeZone: "Mars/Olympus"},
+		{TimeZone: "UTC", Interval: metav1.Duration{Duration: 30 * time.Minute}},
+		{Interval: metav1.Duration{Duration: 0}},
+	}
+
+	// Testing.
+	for _, spec := range specs {
+		_, err := NewRunSchedule(spec)
+		assert.Error(t, err, "Invalid specifications aren't accepted.")
+	}
+}
+
+// Case: get the due run time.
+// Where: stale feature branch has run before, specifications are not changed.
+// Expected: run isn't due until the next scheduled time, missed runs are collapsed into a single run.
+func TestGetDueRunTime(t *testing.T) {
+	// Set up data for tests.
+	var (
+		lastRunTime        = metav1.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		staleFeatureBranch = featurebranchv2.StaleFeatureBranch{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: 2,
+			},
+			Status: featurebranchv2.StaleFeatureBranchStatus{
+				LastRunTime:        &lastRunTime,
+				ObservedGeneration: 2,
+			},
+		}
+	)
+
+	schedule, err := NewRunSchedule(featurebranchv2.StaleFeatureBranchSpec{Interval: metav1.Duration{Duration: 30 * time.Minute}})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	// Testing.
+	dueRunTime, isRunDue := GetDueRunTime(staleFeatureBranch, schedule, lastRunTime.Add(10*time.Minute))
+
+	assert.False(t, isRunDue, "Run isn't due before the scheduled time.")
+	assert.Equal(t, lastRunTime.Add(30*time.Minute), dueRunTime, "Run is due at the scheduled time.")
+
+	now := lastRunTime.Add(5 * time.Hour)
+	dueRunTime, isRunDue = GetDueRunTime(staleFeatureBranch, schedule, now)
+
+	assert.True(t, isRunDue, "Missed run is due.")
+	assert.Equal(t, now, dueRunTime, "Missed runs are collapsed into a single run right now.")
+}
+
+// Case: get the due run time.
+// Where: stale feature branch's specifications are changed since the last run.
+// Expected: run is due right away.
+func TestGetDueRunTimeChangedSpecifications(t *testing.T) {
+	// Set up data for tests.
+	var (
+		lastRunTime        = metav1.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		staleFeatureBranch = featurebranchv2.StaleFeatureBranch{
+			ObjectMeta: metav1.ObjectMeta{
+				Generation: 3,
+			},
+			Status: featurebranchv2.StaleFeatureBranchStatus{
+				LastRunTime:        &lastRunTime,
+				ObservedGeneration: 2,
+			},
+		}
+	)
+
+	schedule, err := NewRunSchedule(featurebranchv2.StaleFeatureBranchSpec{Interval: metav1.Duration{Duration: 30 * time.Minute}})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	// Testing.
+	_, isRunDue := GetDueRunTime(staleFeatureBranch, schedule, lastRunTime.Add(time.Minute))
+
+	assert.True(t, isRunDue, "Run is due right away.")
+}
+
+// Case: get the next run time.
+// Where: namespace failed during the last run is retried before the scheduled time.
+// Expected: next run is at the namespace's retry time.
+func TestGetNextRunTimeFailedNamespaces(t *testing.T) {
+	// Set up data for tests.
+	var (
+		lastRunTime = time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		retryAfter  = metav1.NewTime(lastRunTime.Add(2 * time.Minute))
+		status      = featurebranchv2.StaleFeatureBranchStatus{
+			FailedNamespaces: []featurebranchv2.FailedNamespace{
+				{Name: "project-pr-1", RetryAfter: retryAfter},
+			},
+		}
+	)
+
+	schedule, err := NewRunSchedule(featurebranchv2.StaleFeatureBranchSpec{Interval: metav1.Duration{Duration: 30 * time.Minute}})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	// Testing.
+	nextRunTime := GetNextRunTime(status, schedule, lastRunTime)
+
+	assert.True(t, retryAfter.Time.Equal(nextRunTime), "Next run is at the failed namespace's retry time.")
+	assert.Equal(
+		t,
+		lastRunTime.Add(30*time.Minute),
+		GetNextRunTime(featurebranchv2.StaleFeatureBranchStatus{}, schedule, lastRunTime),
+		"Next run is at the scheduled time without failed namespaces.",
+	)
+}
+
+// Case: get the next run time of a stale feature branch with a cron schedule.
+// Where: namespace failed during the last run is retried outside of the schedule's window.
+// Expected: next run is at the first cron tick at or after the namespace's retry time.
+func TestGetNextRunTimeFailedNamespacesCronSchedule(t *testing.T) {
+	// Set up data for tests.
+	var (
+		lastRunTime = time.Date(2010, time.January, 8, 2, 0, 0, 0, time.UTC)
+		retryAfter  = metav1.NewTime(lastRunTime.Add(2 * time.Minute))
+		status      = featurebranchv2.StaleFeatureBranchStatus{
+			FailedNamespaces: []featurebranchv2.FailedNamespace{
+				{Name: "project-pr-1", RetryAfter: retryAfter},
+			},
+		}
+	)
+
+	schedule, err := NewRunSchedule(featurebranchv2.StaleFeatureBranchSpec{Schedule: "0 2 * * 1-5"})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	// Testing.
+	assert.Equal(
+		t,
+		time.Date(2010, time.January, 11, 2, 0, 0, 0, time.UTC),
+		GetNextRunTime(status, schedule, lastRunTime),
+		"Failed namespace isn't retried on weekends out of the schedule.",
+	)
+}
+
+// Case: compute the next run's time.
+// Where: one of skipped namespaces becomes stale before the scheduled run, another time it becomes stale after it.
+// Expected: next run is at the namespace's stale time only if it's earlier than scheduled.
+func TestGetNextRunTimeNextStaleTime(t *testing.T) {
+	// Set up data for tests.
+	var (
+		lastRunTime      = time.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		earlierStaleTime = metav1.NewTime(lastRunTime.Add(5 * time.Minute))
+		laterStaleTime   = metav1.NewTime(lastRunTime.Add(time.Hour))
+	)
+
+	schedule, err := NewRunSchedule(featurebranchv2.StaleFeatureBranchSpec{Interval: metav1.Duration{Duration: 30 * time.Minute}})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	// Testing.
+	nextRunTime := GetNextRunTime(featurebranchv2.StaleFeatureBranchStatus{NextStaleTime: &earlierStaleTime}, schedule, lastRunTime)
+
+	assert.True(t, earlierStaleTime.Time.Equal(nextRunTime), "Next run is at the time the namespace becomes stale.")
+	assert.Equal(
+		t,
+		lastRunTime.Add(30*time.Minute),
+		GetNextRunTime(featurebranchv2.StaleFeatureBranchStatus{NextStaleTime: &laterStaleTime}, schedule, lastRunTime),
+		"Next run is at the scheduled time if namespaces become stale later.",
+	)
+}
+
+// Case: computing the next run's time of a stale feature branch with a cron schedule whose namespace becomes stale
+// outside of the schedule's window.
+// Where: namespace becomes stale before the next cron tick.
+// Expected: next run is at the first cron tick at or after the time the namespace becomes stale.
+func TestGetNextRunTimeNextStaleTimeCronSchedule(t *testing.T) {
+	// Set up data for tests.
+	var (
+		lastRunTime    = time.Date(2010, time.January, 4, 2, 0, 0, 0, time.UTC)
+		staleTime      = metav1.NewTime(time.Date(2010, time.January, 4, 15, 30, 0, 0, time.UTC))
+		tickStaleTime  = metav1.NewTime(time.Date(2010, time.January, 5, 2, 0, 0, 0, time.UTC))
+		weekendRunTime = time.Date(2010, time.January, 8, 2, 0, 0, 0, time.UTC)
+		weekStaleTime  = metav1.NewTime(time.Date(2010, time.January, 9, 10, 0, 0, 0, time.UTC))
+	)
+
+	schedule, err := NewRunSchedule(featurebranchv2.StaleFeatureBranchSpec{Schedule: "0 2 * * 1-5"})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a run schedule: (%v)", err)
+	}
+
+	// Testing.
+	assert.Equal(
+		t,
+		time.Date(2010, time.January, 5, 2, 0, 0, 0, time.UTC),
+		GetNextRunTime(featurebranchv2.StaleFeatureBranchStatus{NextStaleTime: &staleTime}, schedule, lastRunTime),
+		"Next run is at the next cron tick after the time the namespace becomes stale.",
+	)
+	assert.Equal(
+		t,
+		tickStaleTime.Time,
+		GetNextRunTime(featurebranchv2.StaleFeatureBranchStatus{NextStaleTime: &tickStaleTime}, schedule, lastRunTime),
+		"Next run is at the cron tick the namespace becomes stale at.",
+	)
+	assert.Equal(
+		t,
+		time.Date(2010, time.January, 11, 2, 0, 0, 0, time.UTC),
+		GetNextRunTime(featurebranchv2.StaleFeatureBranchStatus{NextStaleTime: &weekStaleTime}, schedule, weekendRunTime),
+		"Next run is not made on weekends out of the schedule.",
+	)
+}
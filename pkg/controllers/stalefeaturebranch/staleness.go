@@ -0,0 +1,350 @@
+package stalefeaturebranch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NamespaceContents are the namespace's resources staleness strategies detect its last activity by.
+type NamespaceContents struct {
+	Deployments         []appsv1.Deployment
+	StatefulSets        []appsv1.StatefulSet
+	DaemonSets          []appsv1.DaemonSet
+	ControllerRevisions []appsv1.ControllerRevision
+	ReplicaSets         []appsv1.ReplicaSet
+	Pods                []corev1.Pod
+}
+
+// LastActivity is the time of the namespace's last activity and the activity itself, e.g. "rollout of deployment web".
+type LastActivity struct {
+	Time   time.Time
+	Reason string
+}
+
+// StalenessStrategy detects the namespace's last activity by one of signals. If the signal isn't found, the strategy
+// abstains and reports it, so the staleness policy decides by other strategies.
+type StalenessStrategy interface {
+	GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, bool, error)
+}
+
+// NamespaceAgeStrategy detects the namespace's last activity by its creation.
+type NamespaceAgeStrategy struct{}
+
+var _ StalenessStrategy = &NamespaceAgeStrategy{}
+
+func (s *NamespaceAgeStrategy) GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, bool, error) {
+	return getCreationActivity(namespace), true, nil
+}
+
+// LastRolloutStrategy detects the namespace's last activity by rollouts of its workloads: deployments' progressing
+// conditions, controller revisions of stateful sets and daemon sets and creation of workloads and replica sets.
+type LastRolloutStrategy struct{}
+
+var _ StalenessStrategy = &LastRolloutStrategy{}
+
+func (s *LastRolloutStrategy) GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, bool, error) {
+	var lastActivity LastActivity
+
+	for _, deployment := range contents.Deployments {
+		rolloutReason := fmt.Sprintf("rollout of deployment %s", deployment.Name)
+		lastActivity = getLatestActivity(lastActivity, LastActivity{Time: deployment.CreationTimestamp.Time, Reason: rolloutReason})
+
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentProgressing {
+				lastActivity = getLatestActivity(lastActivity, LastActivity{Time: condition.LastUpdateTime.Time, Reason: rolloutReason})
+			}
+		}
+	}
+
+	for _, statefulSet := range contents.StatefulSets {
+		lastActivity = getLatestActivity(lastActivity, LastActivity{
+			Time:   statefulSet.CreationTimestamp.Time,
+			Reason: fmt.Sprintf("rollout of stateful set %s", statefulSet.Name),
+		})
+	}
+
+	for _, daemonSet := range contents.DaemonSets {
+		lastActivity = getLatestActivity(lastActivity, LastActivity{
+			Time:   daemonSet.CreationTimestamp.Time,
+			Reason: fmt.Sprintf("rollout of daemon set %s", daemonSet.Name),
+		})
+	}
+
+	for _, controllerRevision := range contents.ControllerRevisions {
+		lastActivity = getLatestActivity(lastActivity, LastActivity{
+			Time:   controllerRevision.CreationTimestamp.Time,
+			Reason: fmt.Sprintf("rollout of controller revision %s", controllerRevision.Name),
+		})
+	}
+
+	for _, replicaSet := range contents.ReplicaSets {
+		lastActivity = getLatestActivity(lastActivity, LastActivity{
+			Time:   replicaSet.CreationTimestamp.Time,
+			Reason: fmt.Sprintf("rollout of replica set %s", replicaSet.Name),
+		})
+	}
+
+	return getFoundActivity(namespace, lastActivity)
+}
+
+// LastPodStartStrategy detects the namespace's last activity by starts of its pods.
+type LastPodStartStrategy struct{}
+
+var _ StalenessStrategy = &LastPodStartStrategy{}
+
+func (s *LastPodStartStrategy) GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, bool, error) {
+	var lastActivity LastActivity
+
+	for _, pod := range contents.Pods {
+		if pod.Status.StartTime != nil {
+			lastActivity = getLatestActivity(lastActivity, LastActivity{
+				Time:   pod.Status.StartTime.Time,
+				Reason: fmt.Sprintf("start of pod %s", pod.Name),
+			})
+		}
+	}
+
+	return getFoundActivity(namespace, lastActivity)
+}
+
+// AnnotationStrategy detects the namespace's last activity by the time (RFC 3339) of its annotation, e.g. set by
+// continuous integration on each pipeline.
+type AnnotationStrategy struct {
+	Annotation string
+}
+
+var _ StalenessStrategy = &AnnotationStrategy{}
+
+func (s *AnnotationStrategy) GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, bool, error) {
+	annotationValue, isAnnotated := namespace.Annotations[s.Annotation]
+
+	if !isAnnotated {
+		return LastActivity{}, false, nil
+	}
+
+	annotationTime, err := time.Parse(time.RFC3339, annotationValue)
+
+	if err != nil {
+		return LastActivity{}, false, fmt.Errorf("invalid time of annotation %s: %v", s.Annotation, err)
+	}
+
+	return getFoundActivity(namespace, LastActivity{
+		Time:   annotationTime,
+		Reason: fmt.Sprintf("annotation %s", s.Annotation),
+	})
+}
+
+// HTTPStrategy detects the namespace's last activity by an external HTTP endpoint, e.g. a service asking a Git
+// provider about the last commit. The endpoint responds with JSON containing lastActivityTime (RFC 3339) and,
+// optionally, reason.
+type HTTPStrategy struct {
+	URL    string
+	Client *http.Client
+}
+
+var _ StalenessStrategy = &HTTPStrategy{}
+
+// httpLastActivity is the response of the HTTP strategy's endpoint.
+type httpLastActivity struct {
+	LastActivityTime time.Time `json:"lastActivityTime"`
+	Reason           string    `json:"reason"`
+}
+
+func (s *HTTPStrategy) GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, bool, error) {
+	url := strings.ReplaceAll(s.URL, namespaceURLPlaceholder, namespace.Name)
+
+	response, err := s.Client.Get(url)
+
+	if err != nil {
+		return LastActivity{}, false, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return LastActivity{}, false, fmt.Errorf("unexpected status of %s: %s", url, response.Status)
+	}
+
+	var body httpLastActivity
+
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return LastActivity{}, false, fmt.Errorf("invalid response of %s: %v", url, err)
+	}
+
+	if body.LastActivityTime.IsZero() {
+		return LastActivity{}, false, fmt.Errorf("response of %s doesn't contain last activity time", url)
+	}
+
+	if body.Reason == "" {
+		body.Reason = fmt.Sprintf("HTTP check %s", url)
+	}
+
+	return getFoundActivity(namespace, LastActivity{
+		Time:   body.LastActivityTime,
+		Reason: body.Reason,
+	})
+}
+
+// StalenessPolicy combines staleness strategies selected by the stale feature branch's specifications.
+type StalenessPolicy struct {
+	strategies  []StalenessStrategy
+	combination featurebranchv2.StalenessCombinationType
+}
+
+// NewStalenessPolicy validates the stale feature branch's staleness specifications and creates a policy for them. If
+// they aren't specified, a namespace is stale if its creation, rollouts and pods' starts are all stale.
+func NewStalenessPolicy(spec featurebranchv2.StaleFeatureBranchSpec) (*StalenessPolicy, error) {
+	if spec.Staleness == nil {
+		return &StalenessPolicy{
+			strategies: []StalenessStrategy{
+				&NamespaceAgeStrategy{},
+				&LastRolloutStrategy{},
+				&LastPodStartStrategy{},
+			},
+			combination: featurebranchv2.AllStalenessCombinationType,
+		}, nil
+	}
+
+	if len(spec.Staleness.Strategies) == 0 {
+		return nil, errors.New("at least one staleness strategy is required")
+	}
+
+	policy := &StalenessPolicy{combination: spec.Staleness.Combination}
+
+	switch policy.combination {
+	case featurebranchv2.AllStalenessCombinationType, featurebranchv2.AnyStalenessCombinationType:
+	case "":
+		policy.combination = featurebranchv2.AllStalenessCombinationType
+	default:
+		return nil, fmt.Errorf("unknown staleness combination %q", spec.Staleness.Combination)
+	}
+
+	for _, strategySpec := range spec.Staleness.Strategies {
+		strategy, err := newStalenessStrategy(strategySpec)
+
+		if err != nil {
+			return nil, err
+		}
+
+		policy.strategies = append(policy.strategies, strategy)
+	}
+
+	return policy, nil
+}
+
+// GetLastActivity returns the namespace's last activity by strategies that found their signals. A namespace is stale if
+// all strategies find it stale when they are combined by All, so the latest activity is returned. Otherwise, a
+// namespace is stale if any of strategies finds it stale, so the earliest activity is returned. If all strategies
+// abstain, the namespace's creation is its last activity.
+func (p *StalenessPolicy) GetLastActivity(namespace corev1.Namespace, contents NamespaceContents) (LastActivity, error) {
+	var lastActivity LastActivity
+
+	isFound := false
+
+	for _, strategy := range p.strategies {
+		activity, isActivityFound, err := strategy.GetLastActivity(namespace, contents)
+
+		if err != nil {
+			return LastActivity{}, err
+		}
+
+		switch {
+		case !isActivityFound:
+		case !isFound:
+			lastActivity = activity
+			isFound = true
+		case p.combination == featurebranchv2.AnyStalenessCombinationType && activity.Time.Before(lastActivity.Time):
+			lastActivity = activity
+		case p.combination == featurebranchv2.AllStalenessCombinationType:
+			lastActivity = getLatestActivity(lastActivity, activity)
+		}
+	}
+
+	if !isFound {
+		return getCreationActivity(namespace), nil
+	}
+
+	return lastActivity, nil
+}
+
+// ValidateStalenessScope rejects the HTTP staleness strategy of stale feature branches that aren't cluster-scoped. The
+// operator requests the strategy's URL itself, so a namespace's owner could make it request any endpoint the operator
+// reaches, while cluster stale feature branches are created by cluster administrators only.
+func ValidateStalenessScope(staleFeatureBranch featurebranchv2.StaleFeatureBranch) error {
+	if staleFeatureBranch.Spec.Staleness == nil || IsClusterScoped(staleFeatureBranch) {
+		return nil
+	}
+
+	for _, strategySpec := range staleFeatureBranch.Spec.Staleness.Strategies {
+		if strategySpec.Type == featurebranchv2.HTTPStalenessStrategyType {
+			return fmt.Errorf("%s staleness strategy is allowed for cluster stale feature branches only", strategySpec.Type)
+		}
+	}
+
+	return nil
+}
+
+func newStalenessStrategy(spec featurebranchv2.StalenessStrategySpec) (StalenessStrategy, error) {
+	switch spec.Type {
+	case featurebranchv2.NamespaceAgeStalenessStrategyType:
+		return &NamespaceAgeStrategy{}, nil
+	case featurebranchv2.LastRolloutStalenessStrategyType:
+		return &LastRolloutStrategy{}, nil
+	case featurebranchv2.LastPodStartStalenessStrategyType:
+		return &LastPodStartStrategy{}, nil
+	case featurebranchv2.AnnotationStalenessStrategyType:
+		if spec.Annotation == "" {
+			return nil, fmt.Errorf("annotation is required by %s staleness strategy", spec.Type)
+		}
+
+		return &AnnotationStrategy{Annotation: spec.Annotation}, nil
+	case featurebranchv2.HTTPStalenessStrategyType:
+		if spec.HTTP == nil || spec.HTTP.URL == "" {
+			return nil, fmt.Errorf("http url is required by %s staleness strategy", spec.Type)
+		}
+
+		timeoutSeconds := spec.HTTP.TimeoutSeconds
+
+		if timeoutSeconds == 0 {
+			timeoutSeconds = defaultHTTPStalenessTimeoutSeconds
+		}
+
+		return &HTTPStrategy{
+			URL:    spec.HTTP.URL,
+			Client: &http.Client{Timeout: time.Duration(timeoutSeconds) * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown staleness strategy %q", spec.Type)
+	}
+}
+
+func getCreationActivity(namespace corev1.Namespace) LastActivity {
+	return LastActivity{Time: namespace.CreationTimestamp.Time, Reason: "creation"}
+}
+
+// getFoundActivity reports whether the strategy found the activity, activities before the namespace's creation are
+// replaced by the creation.
+func getFoundActivity(namespace corev1.Namespace, lastActivity LastActivity) (LastActivity, bool, error) {
+	if lastActivity.Time.IsZero() {
+		return LastActivity{}, false, nil
+	}
+
+	return getLatestActivity(getCreationActivity(namespace), lastActivity), true, nil
+}
+
+func getLatestActivity(first LastActivity, second LastActivity) LastActivity {
+	if second.Time.After(first.Time) {
+		return second
+	}
+
+	return first
+}
@@ -0,0 +1,444 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: get namespace's last activity by staleness strategies combined by All and Any.
+// Where: namespace is created first, its deployment is rolled out next and its pod is started last.
+// Expected: the latest activity is returned if strategies are combined by All, the earliest one is returned otherwise.
+func TestStalenessPolicyCombination(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceCreationTimestamp = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		deploymentTimestamp        = metav1.Date(2010, time.January, 2, 0, 0, 0, 0, time.UTC)
+		podStartTimestamp          = metav1.Date(2010, time.January, 3, 0, 0, 0, 0, time.UTC)
+	)
+
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	contents := NamespaceContents{
+		Deployments: []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Name: "back-end", CreationTimestamp: deploymentTimestamp}},
+		},
+		Pods: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "back-end-848d5fdff6-rpmzw"},
+				Status:     corev1.PodStatus{StartTime: &podStartTimestamp},
+			},
+		},
+	}
+
+	strategies := []featurebranchv2.StalenessStrategySpec{
+		{Type: featurebranchv2.LastRolloutStalenessStrategyType},
+		{Type: featurebranchv2.LastPodStartStalenessStrategyType},
+	}
+
+	testCases := []struct {
+		combination  featurebranchv2.StalenessCombinationType
+		lastActivity LastActivity
+	}{
+		{
+			combination:  featurebranchv2.AllStalenessCombinationType,
+			lastActivity: LastActivity{Time: podStartTimestamp.Time, Reason: "start of pod back-end-848d5fdff6-rpmzw"},
+		},
+		{
+			combination:  featurebranchv2.AnyStalenessCombinationType,
+			lastActivity: LastActivity{Time: deploymentTimestamp.Time, Reason: "rollout of deployment back-end"},
+		},
+	}
+
+	// Testing.
+	for _, testCase := range testCases {
+		policy, err := NewStalenessPolicy(featurebranchv2.StaleFeatureBranchSpec{
+			Staleness: &featurebranchv2.StalenessSpec{
+				Strategies:  strategies,
+				Combination: testCase.combination,
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("An error occurred while creating a staleness policy: (%v)", err)
+		}
+
+		lastActivity, err := policy.GetLastActivity(namespace, contents)
+
+		if err != nil {
+			t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+		}
+
+		assert.Equal(t, testCase.lastActivity, lastActivity, fmt.Sprintf("Last activity is chosen by %s.", testCase.combination))
+	}
+}
+
+// Case: get namespace's last activity by staleness strategies combined by Any.
+// Where: namespace created long ago isn't annotated, its deployment is rolled out recently, or it's empty.
+// Expected: annotation strategy abstains and the rollout is returned, creation is returned if all strategies abstain.
+func TestStalenessPolicyAbstainingStrategy(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceCreationTimestamp = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		deploymentTimestamp        = metav1.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+	)
+
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	contents := NamespaceContents{
+		Deployments: []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Name: "back-end", CreationTimestamp: deploymentTimestamp}},
+		},
+	}
+
+	policy, err := NewStalenessPolicy(featurebranchv2.StaleFeatureBranchSpec{
+		Staleness: &featurebranchv2.StalenessSpec{
+			Strategies: []featurebranchv2.StalenessStrategySpec{
+				{Type: featurebranchv2.AnnotationStalenessStrategyType, Annotation: "ci.example.com/last-pipeline-at"},
+				{Type: featurebranchv2.LastRolloutStalenessStrategyType},
+			},
+			Combination: featurebranchv2.AnyStalenessCombinationType,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a staleness policy: (%v)", err)
+	}
+
+	// Testing.
+	lastActivity, err := policy.GetLastActivity(namespace, contents)
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		LastActivity{Time: deploymentTimestamp.Time, Reason: "rollout of deployment back-end"},
+		lastActivity,
+		"Fresh rollout is the last activity as the annotation strategy abstains.",
+	)
+
+	lastActivity, err = policy.GetLastActivity(namespace, NamespaceContents{})
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.Equal(
+		t,
+		LastActivity{Time: namespaceCreationTimestamp.Time, Reason: "creation"},
+		lastActivity,
+		"Namespace creation is the last activity as all strategies abstain.",
+	)
+}
+
+// Case: get namespace's last activity by last rollout staleness strategy.
+// Where: namespace contains replica sets, controller revisions and pods created at different time, or nothing at all.
+// Expected: the most recent rollout is returned, pods' starts are ignored, the strategy abstains if nothing is
+// deployed.
+func TestLastRolloutStrategy(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceCreationTimestamp  = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		replicaSetTimestamp         = metav1.Date(2010, time.January, 2, 0, 0, 0, 0, time.UTC)
+		controllerRevisionTimestamp = metav1.Date(2010, time.January, 3, 0, 0, 0, 0, time.UTC)
+		podStartTimestamp           = metav1.Date(2010, time.January, 4, 0, 0, 0, 0, time.UTC)
+		strategy                    = &LastRolloutStrategy{}
+	)
+
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	contents := NamespaceContents{
+		ReplicaSets: []appsv1.ReplicaSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-848d5fdff6", CreationTimestamp: replicaSetTimestamp}},
+		},
+		ControllerRevisions: []appsv1.ControllerRevision{
+			{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-database-5d8f7b9c4", CreationTimestamp: controllerRevisionTimestamp}},
+		},
+		Pods: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-848d5fdff6-rpmzw", CreationTimestamp: replicaSetTimestamp},
+				Status:     corev1.PodStatus{StartTime: &podStartTimestamp},
+			},
+		},
+	}
+
+	// Testing.
+	lastActivity, isFound, err := strategy.GetLastActivity(namespace, contents)
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.True(t, isFound, "Rollout is found.")
+	assert.Equal(
+		t,
+		LastActivity{Time: controllerRevisionTimestamp.Time, Reason: "rollout of controller revision project-pr-1-database-5d8f7b9c4"},
+		lastActivity,
+		"The most recent rollout in the namespace is the last activity.",
+	)
+
+	_, isFound, err = strategy.GetLastActivity(namespace, NamespaceContents{})
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.False(t, isFound, "Strategy abstains as nothing is deployed.")
+}
+
+// Case: get namespace's last activity by last pod start staleness strategy.
+// Where: namespace contains a replica set and pods started at different time and not started yet, or nothing at all.
+// Expected: the most recent pod start is returned, the strategy abstains if no pods are started.
+func TestLastPodStartStrategy(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespaceCreationTimestamp = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		replicaSetTimestamp        = metav1.Date(2010, time.January, 2, 0, 0, 0, 0, time.UTC)
+		firstPodStartTimestamp     = metav1.Date(2010, time.January, 3, 0, 0, 0, 0, time.UTC)
+		secondPodStartTimestamp    = metav1.Date(2010, time.January, 4, 0, 0, 0, 0, time.UTC)
+		strategy                   = &LastPodStartStrategy{}
+	)
+
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	contents := NamespaceContents{
+		ReplicaSets: []appsv1.ReplicaSet{
+			{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-848d5fdff6", CreationTimestamp: replicaSetTimestamp}},
+		},
+		Pods: []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-848d5fdff6-rpmzw"},
+				Status:     corev1.PodStatus{StartTime: &secondPodStartTimestamp},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-848d5fdff6-m7sch"},
+				Status:     corev1.PodStatus{StartTime: &firstPodStartTimestamp},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1-848d5fdff6-x2k9q"},
+			},
+		},
+	}
+
+	// Testing.
+	lastActivity, isFound, err := strategy.GetLastActivity(namespace, contents)
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.True(t, isFound, "Pod start is found.")
+	assert.Equal(
+		t,
+		LastActivity{Time: secondPodStartTimestamp.Time, Reason: "start of pod project-pr-1-848d5fdff6-rpmzw"},
+		lastActivity,
+		"The most recent pod start in the namespace is the last activity.",
+	)
+
+	_, isFound, err = strategy.GetLastActivity(namespace, NamespaceContents{})
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.False(t, isFound, "Strategy abstains as no pods are started.")
+}
+
+// Case: get namespace's last activity by annotation staleness strategy.
+// Where: namespace is annotated with a time after its creation, another namespace isn't annotated.
+// Expected: the annotation's time is returned for the annotated namespace, the strategy abstains for another one.
+func TestAnnotationStrategy(t *testing.T) {
+	// Set up data for tests.
+	var (
+		annotation                 = "ci.example.com/last-pipeline-at"
+		namespaceCreationTimestamp = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		annotationTime             = time.Date(2010, time.January, 5, 0, 0, 0, 0, time.UTC)
+		strategy                   = &AnnotationStrategy{Annotation: annotation}
+	)
+
+	annotatedNamespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: namespaceCreationTimestamp,
+			Annotations:       map[string]string{annotation: annotationTime.Format(time.RFC3339)},
+		},
+	}
+
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			CreationTimestamp: namespaceCreationTimestamp,
+		},
+	}
+
+	// Testing.
+	lastActivity, isFound, err := strategy.GetLastActivity(annotatedNamespace, NamespaceContents{})
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.True(t, isFound, "Annotation is found.")
+	assert.Equal(
+		t,
+		LastActivity{Time: annotationTime, Reason: "annotation " + annotation},
+		lastActivity,
+		"Annotation's time is the last activity.",
+	)
+
+	_, isFound, err = strategy.GetLastActivity(namespace, NamespaceContents{})
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.False(t, isFound, "Strategy abstains as the namespace isn't annotated.")
+}
+
+// Case: get namespace's last activity by HTTP staleness strategy.
+// Where: endpoint responds with the last activity of the requested namespace.
+// Expected: the namespace's name is substituted to the URL, the endpoint's last activity is returned.
+func TestHTTPStrategy(t *testing.T) {
+	// Set up data for tests.
+	var (
+		requestedPath string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		requestedPath = request.URL.Path
+		fmt.Fprint(writer, `{"lastActivityTime": "2010-01-05T00:00:00Z", "reason": "commit to branch pr-1"}`)
+	}))
+
+	defer server.Close()
+
+	policy, err := NewStalenessPolicy(featurebranchv2.StaleFeatureBranchSpec{
+		Staleness: &featurebranchv2.StalenessSpec{
+			Strategies: []featurebranchv2.StalenessStrategySpec{
+				{
+					Type: featurebranchv2.HTTPStalenessStrategyType,
+					HTTP: &featurebranchv2.HTTPStalenessSpec{URL: server.URL + "/activities/{namespace}"},
+				},
+			},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a staleness policy: (%v)", err)
+	}
+
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			CreationTimestamp: metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	// Testing.
+	lastActivity, err := policy.GetLastActivity(namespace, NamespaceContents{})
+
+	if err != nil {
+		t.Fatalf("An error occurred while getting the namespace's last activity: (%v)", err)
+	}
+
+	assert.Equal(t, "/activities/project-pr-1", requestedPath, "Namespace's name is substituted to the URL.")
+	assert.Equal(
+		t,
+		LastActivity{Time: time.Date(2010, time.January, 5, 0, 0, 0, 0, time.UTC), Reason: "commit to branch pr-1"},
+		lastActivity,
+		"Endpoint's last activity is the last activity.",
+	)
+}
+
+// Case: create staleness policy.
+// Where: staleness strategies are misconfigured.
+// Expected: an error is returned.
+func TestNewStalenessPolicyInvalid(t *testing.T) {
+	// Set up data for tests.
+	invalidStalenessSpecs := []*featurebranchv2.StalenessSpec{
+		{},
+		{Strategies: []featurebranchv2.StalenessStrategySpec{{Type: "Unknown"}}},
+		{Strategies: []featurebranchv2.StalenessStrategySpec{{Type: featurebranchv2.AnnotationStalenessStrategyType}}},
+		{Strategies: []featurebranchv2.StalenessStrategySpec{{Type: featurebranchv2.HTTPStalenessStrategyType}}},
+		{
+			Strategies:  []featurebranchv2.StalenessStrategySpec{{Type: featurebranchv2.NamespaceAgeStalenessStrategyType}},
+			Combination: "Unknown",
+		},
+	}
+
+	// Testing.
+	for _, stalenessSpec := range invalidStalenessSpecs {
+		_, err := NewStalenessPolicy(featurebranchv2.StaleFeatureBranchSpec{Staleness: stalenessSpec})
+		assert.Error(t, err, "Misconfigured staleness strategies are rejected.")
+	}
+}
+
+// Case: validate the scope of staleness strategies.
+// Where: stale feature branch or cluster stale feature branch uses the HTTP strategy.
+// Expected: HTTP strategy is allowed for the cluster stale feature branch only.
+func TestValidateStalenessScope(t *testing.T) {
+	// Set up data for tests.
+	spec := featurebranchv2.StaleFeatureBranchSpec{
+		Staleness: &featurebranchv2.StalenessSpec{
+			Strategies: []featurebranchv2.StalenessStrategySpec{
+				{Type: featurebranchv2.NamespaceAgeStalenessStrategyType},
+				{
+					Type: featurebranchv2.HTTPStalenessStrategyType,
+					HTTP: &featurebranchv2.HTTPStalenessSpec{URL: "http://169.254.169.254/{namespace}"},
+				},
+			},
+		},
+	}
+
+	staleFeatureBranch := featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "team-a"},
+		Spec:       spec,
+	}
+
+	clusterStaleFeatureBranch := NewClusterScopedStaleFeatureBranch(featurebranchv2.ClusterStaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-stale-feature-branch"},
+		Spec:       spec,
+	})
+
+	// Testing.
+	assert.EqualError(
+		t,
+		ValidateStalenessScope(staleFeatureBranch),
+		"HTTP staleness strategy is allowed for cluster stale feature branches only",
+		"HTTP strategy of the stale feature branch is rejected.",
+	)
+	assert.NoError(t, ValidateStalenessScope(clusterStaleFeatureBranch), "HTTP strategy of the cluster stale feature branch is allowed.")
+
+	staleFeatureBranch.Spec.Staleness = nil
+	assert.NoError(t, ValidateStalenessScope(staleFeatureBranch), "Stale feature branch without the HTTP strategy is allowed.")
+}
@@ -0,0 +1,188 @@
+package stalefeaturebranch
+
+import (
+	"fmt"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetRunStatus records the run's time, the next run's time and the run's result to the stale feature branch's status.
+func SetRunStatus(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, runTime metav1.Time, nextRunTime metav1.Time, runErr error) {
+	status := &staleFeatureBranch.Status
+
+	status.LastRunTime = &runTime
+	status.NextRunTime = &nextRunTime
+	status.ObservedGeneration = staleFeatureBranch.Generation
+
+	if runErr != nil {
+		status.LastError = runErr.Error()
+
+		SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+			Type:    featurebranchv2.StaleFeatureBranchReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  ProcessingFailedConditionReason,
+			Message: runErr.Error(),
+		})
+
+		SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+			Type:    featurebranchv2.StaleFeatureBranchDegraded,
+			Status:  corev1.ConditionTrue,
+			Reason:  ProcessingFailedConditionReason,
+			Message: runErr.Error(),
+		})
+
+		return
+	}
+
+	status.LastError = ""
+
+	SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+		Type:   featurebranchv2.StaleFeatureBranchReady,
+		Status: corev1.ConditionTrue,
+		Reason: ProcessingSucceededConditionReason,
+	})
+
+	SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+		Type:   featurebranchv2.StaleFeatureBranchDegraded,
+		Status: corev1.ConditionFalse,
+		Reason: ProcessingSucceededConditionReason,
+	})
+}
+
+// SetInvalidSpecificationsStatus records the stale feature branch's specifications error to its status.
+func SetInvalidSpecificationsStatus(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, specErr error) {
+	status := &staleFeatureBranch.Status
+
+	status.NextRunTime = nil
+	status.NextStaleTime = nil
+	status.ObservedGeneration = staleFeatureBranch.Generation
+	status.LastError = specErr.Error()
+
+	SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+		Type:    featurebranchv2.StaleFeatureBranchReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  InvalidSpecificationsConditionReason,
+		Message: specErr.Error(),
+	})
+
+	SetCondition(status, featurebranchv2.StaleFeatureBranchCondition{
+		Type:    featurebranchv2.StaleFeatureBranchDegraded,
+		Status:  corev1.ConditionTrue,
+		Reason:  InvalidSpecificationsConditionReason,
+		Message: specErr.Error(),
+	})
+}
+
+// MatchNamespace records the matched namespace and its captures to the status. Only the first matched namespaces are
+// listed, the rest are counted.
+func MatchNamespace(status *featurebranchv2.StaleFeatureBranchStatus, namespace corev1.Namespace, captures map[string]string) {
+	status.MatchedNamespacesCount++
+
+	if len(status.MatchedNamespaces) < statusNamespacesLimit {
+		status.MatchedNamespaces = append(status.MatchedNamespaces, featurebranchv2.MatchedNamespace{
+			Name:     namespace.Name,
+			Captures: captures,
+		})
+	}
+}
+
+// SkipNamespace records the matched namespace that isn't deleted and the reason to the status. Only the first skipped
+// namespaces are listed, the rest are counted by their reasons.
+func SkipNamespace(status *featurebranchv2.StaleFeatureBranchStatus, namespace corev1.Namespace, reason string, message string) {
+	status.SkippedNamespacesCount++
+
+	if status.SkippedNamespacesReasons == nil {
+		status.SkippedNamespacesReasons = make(map[string]int)
+	}
+
+	status.SkippedNamespacesReasons[reason]++
+
+	if len(status.SkippedNamespaces) < statusNamespacesLimit {
+		status.SkippedNamespaces = append(status.SkippedNamespaces, featurebranchv2.SkippedNamespace{
+			Name:    namespace.Name,
+			Reason:  reason,
+			Message: message,
+		})
+	}
+}
+
+// SetNextStaleTime records the time the skipped namespace becomes stale to the status if it's earlier than the
+// recorded one. Zero time means the namespace's stale time is unknown.
+func SetNextStaleTime(status *featurebranchv2.StaleFeatureBranchStatus, staleTime time.Time) {
+	if staleTime.IsZero() {
+		return
+	}
+
+	if status.NextStaleTime == nil || staleTime.Before(status.NextStaleTime.Time) {
+		nextStaleTime := metav1.NewTime(staleTime)
+		status.NextStaleTime = &nextStaleTime
+	}
+}
+
+// FailNamespace records the namespace failed to be processed by the operation to the status. Namespace that failed
+// during the previous run as well is retried after a twice longer delay. Returns the failure as an error.
+func FailNamespace(status *featurebranchv2.StaleFeatureBranchStatus, previousFailedNamespaces []featurebranchv2.FailedNamespace, namespace corev1.Namespace, operation string, err error) error {
+	failure := fmt.Errorf("unable to %s namespace %s: %v", operation, namespace.Name, err)
+	failures := 1
+
+	for _, previousFailedNamespace := range previousFailedNamespaces {
+		if previousFailedNamespace.Name == namespace.Name {
+			failures = previousFailedNamespace.Failures + 1
+		}
+	}
+
+	retryDelay := failedNamespaceRetryBaseDelay
+
+	for retry := 1; retry < failures && retryDelay < failedNamespaceRetryMaxDelay; retry++ {
+		retryDelay *= 2
+	}
+
+	if retryDelay > failedNamespaceRetryMaxDelay {
+		retryDelay = failedNamespaceRetryMaxDelay
+	}
+
+	status.FailedNamespaces = append(status.FailedNamespaces, featurebranchv2.FailedNamespace{
+		Name:       namespace.Name,
+		Message:    failure.Error(),
+		Failures:   failures,
+		RetryAfter: metav1.NewTime(metav1.Now().Add(retryDelay)),
+	})
+
+	return failure
+}
+
+// SetCondition adds the condition to the status or updates the existing one of the same type. Transition time is
+// changed only if the condition's status is changed.
+func SetCondition(status *featurebranchv2.StaleFeatureBranchStatus, condition featurebranchv2.StaleFeatureBranchCondition) {
+	condition.LastTransitionTime = metav1.Now()
+
+	for index, existingCondition := range status.Conditions {
+		if existingCondition.Type != condition.Type {
+			continue
+		}
+
+		if existingCondition.Status == condition.Status {
+			condition.LastTransitionTime = existingCondition.LastTransitionTime
+		}
+
+		status.Conditions[index] = condition
+		return
+	}
+
+	status.Conditions = append(status.Conditions, condition)
+}
+
+// GetCondition returns the condition of the type from the status or nil if it's absent.
+func GetCondition(status featurebranchv2.StaleFeatureBranchStatus, conditionType featurebranchv2.StaleFeatureBranchConditionType) *featurebranchv2.StaleFeatureBranchCondition {
+	for index := range status.Conditions {
+		if status.Conditions[index].Type == conditionType {
+			return &status.Conditions[index]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package stalefeaturebranch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: set a condition to a status.
+// Where: condition of the same type and status already exists.
+// Expected: condition is updated, but its transition time is kept.
+func TestSetConditionSameStatus(t *testing.T) {
+	// Set up data for tests.
+	var (
+		transitionTime = metav1.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)
+		status         = featurebranchv2.StaleFeatureBranchStatus{
+			Conditions: []featurebranchv2.StaleFeatureBranchCondition{
+				{
+					Type:               featurebranchv2.StaleFeatureBranchDegraded,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: transitionTime,
+					Message:            "first error",
+				},
+			},
+		}
+	)
+
+	// Testing.
+	SetCondition(&status, featurebranchv2.StaleFeatureBranchCondition{
+		Type:    featurebranchv2.StaleFeatureBranchDegraded,
+		Status:  corev1.ConditionTrue,
+		Message: "second error",
+	})
+
+	assert.Equal(t, 1, len(status.Conditions), "Condition of the same type isn't duplicated.")
+	assert.Equal(t, "second error", status.Conditions[0].Message, "Condition's message is updated.")
+	assert.Equal(t, transitionTime, status.Conditions[0].LastTransitionTime, "Transition time is kept.")
+}
+
+// Case: record matched and skipped namespaces to a status.
+// Where: there are more namespaces than the status lists.
+// Expected: all namespaces are counted, skipped ones by reasons, but only the first ones are listed.
+func TestMatchAndSkipNamespaceLimit(t *testing.T) {
+	// Set up data for tests.
+	var (
+		namespacesCount = statusNamespacesLimit + 10
+		status          featurebranchv2.StaleFeatureBranchStatus
+	)
+
+	// Testing.
+	for i := 0; i < namespacesCount; i++ {
+		namespace := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("project-pr-%d", i),
+			},
+		}
+
+		MatchNamespace(&status, namespace, nil)
+		SkipNamespace(&status, namespace, NotStaleSkipReason, "")
+	}
+
+	assert.Equal(t, namespacesCount, status.MatchedNamespacesCount, "All matched namespaces are counted.")
+	assert.Equal(t, namespacesCount, status.SkippedNamespacesCount, "All skipped namespaces are counted.")
+	assert.Equal(
+		t,
+		map[string]int{NotStaleSkipReason: namespacesCount},
+		status.SkippedNamespacesReasons,
+		"Skipped namespaces are counted by reasons.",
+	)
+	assert.Equal(t, statusNamespacesLimit, len(status.MatchedNamespaces), "Only the first matched namespaces are listed.")
+	assert.Equal(t, statusNamespacesLimit, len(status.SkippedNamespaces), "Only the first skipped namespaces are listed.")
+	assert.Equal(t, "project-pr-0", status.SkippedNamespaces[0].Name, "The first skipped namespace is listed.")
+}
+
+// Case: set the run's status.
+// Where: the run failed.
+// Expected: error is recorded, stale feature branch isn't ready and is degraded.
+func TestSetRunStatusFailed(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranch featurebranchv2.StaleFeatureBranch
+		runErr             = errors.New("namespaces are forbidden")
+	)
+
+	// Testing.
+	SetRunStatus(&staleFeatureBranch, metav1.Now(), metav1.Now(), runErr)
+
+	status := staleFeatureBranch.Status
+
+	assert.Equal(t, runErr.Error(), status.LastError, "Error is recorded to the status.")
+	assert.Equal(
+		t,
+		corev1.ConditionFalse,
+		GetCondition(status, featurebranchv2.StaleFeatureBranchReady).Status,
+		"Stale feature branch isn't ready.",
+	)
+	assert.Equal(
+		t,
+		corev1.ConditionTrue,
+		GetCondition(status, featurebranchv2.StaleFeatureBranchDegraded).Status,
+		"Stale feature branch is degraded.",
+	)
+}
+
+// Case: record a failed namespace to a status.
+// Where: namespace failed during the previous runs as well.
+// Expected: failures are counted, namespace is retried after a twice longer delay, but not longer than an hour.
+func TestFailNamespace(t *testing.T) {
+	// Set up data for tests.
+	var (
+		status    featurebranchv2.StaleFeatureBranchStatus
+		namespace = corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}}
+		failure   = errors.New("namespace deletion is forbidden")
+	)
+
+	previousFailedNamespaces := []featurebranchv2.FailedNamespace{
+		{Name: "project-pr-1", Failures: 2},
+	}
+
+	// Testing.
+	err := FailNamespace(&status, previousFailedNamespaces, namespace, "delete", failure)
+
+	assert.EqualError(t, err, "unable to delete namespace project-pr-1: namespace deletion is forbidden", "Failure is returned.")
+
+	failedNamespace := status.FailedNamespaces[0]
+	retryDelay := time.Until(failedNamespace.RetryAfter.Time)
+
+	assert.Equal(t, 3, failedNamespace.Failures, "Failures are counted across runs.")
+	assert.Equal(t, err.Error(), failedNamespace.Message, "Failure is recorded to the status.")
+	assert.True(t, retryDelay > 3*time.Minute && retryDelay <= 4*time.Minute, "Namespace is retried in 4 minutes.")
+
+	previousFailedNamespaces[0].Failures = 100
+	status.FailedNamespaces = nil
+
+	FailNamespace(&status, previousFailedNamespaces, namespace, "delete", failure)
+
+	retryDelay = time.Until(status.FailedNamespaces[0].RetryAfter.Time)
+
+	assert.True(t, retryDelay > 59*time.Minute && retryDelay <= time.Hour, "Namespace is retried in an hour at most.")
+}
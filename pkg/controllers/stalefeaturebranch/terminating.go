@@ -0,0 +1,262 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// leftoverResource is a well-known kind of resources that commonly keep a namespace terminating by their finalizers.
+type leftoverResource struct {
+	name string
+	list runtime.Object
+}
+
+func newLeftoverResources() []leftoverResource {
+	return []leftoverResource{
+		{name: "persistentvolumeclaims", list: &corev1.PersistentVolumeClaimList{}},
+		{name: "pods", list: &corev1.PodList{}},
+		{name: "services", list: &corev1.ServiceList{}},
+		{name: "configmaps", list: &corev1.ConfigMapList{}},
+		{name: "secrets", list: &corev1.SecretList{}},
+	}
+}
+
+// IsNamespaceTerminating reports whether the namespace is already being deleted.
+func IsNamespaceTerminating(namespace corev1.Namespace) bool {
+	return namespace.DeletionTimestamp != nil
+}
+
+// IsNamespaceDeleted reports whether the namespace is already deleted or being deleted by a live read from the API
+// server, e.g. by another stale feature branch's run since the namespace was read from the informer cache.
+func (r *ReconcileStaleFeatureBranch) IsNamespaceDeleted(namespace corev1.Namespace) (bool, error) {
+	var liveNamespace corev1.Namespace
+
+	if err := r.getAPIReader().Get(context.TODO(), types.NamespacedName{Name: namespace.Name}, &liveNamespace); err != nil {
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return IsNamespaceTerminating(liveNamespace), nil
+}
+
+// getAPIReader returns the reader of the API server bypassing the informer cache, or the client if it isn't set.
+func (r *ReconcileStaleFeatureBranch) getAPIReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+
+	return r.APIReader
+}
+
+// IsNamespaceStuckTerminating reports whether the namespace is being deleted for the threshold or longer.
+func IsNamespaceStuckTerminating(namespace corev1.Namespace, threshold time.Duration, now time.Time) bool {
+	return IsNamespaceTerminating(namespace) && !now.Before(namespace.DeletionTimestamp.Add(threshold))
+}
+
+// InspectStuckNamespace collects finalizers blocking the namespace's deletion and kinds of its remaining resources from
+// the namespace's finalizers and conditions and from its well-known leftover resources.
+func (r *ReconcileStaleFeatureBranch) InspectStuckNamespace(namespace corev1.Namespace) (featurebranchv2.StuckNamespace, error) {
+	finalizers := make(map[string]bool)
+	remainingResources := make(map[string]bool)
+
+	for _, finalizer := range namespace.Spec.Finalizers {
+		finalizers[string(finalizer)] = true
+	}
+
+	for _, finalizer := range namespace.Finalizers {
+		finalizers[finalizer] = true
+	}
+
+	for _, resource := range newLeftoverResources() {
+		objects, err := r.listLeftoverObjects(namespace, resource)
+
+		if err != nil {
+			return featurebranchv2.StuckNamespace{}, err
+		}
+
+		for _, object := range objects {
+			remainingResources[resource.name] = true
+
+			for _, finalizer := range object.GetFinalizers() {
+				finalizers[finalizer] = true
+			}
+		}
+	}
+
+	var messages []string
+
+	for _, condition := range namespace.Status.Conditions {
+		isReported := condition.Type == corev1.NamespaceContentRemaining || condition.Type == corev1.NamespaceFinalizersRemaining
+
+		if isReported && condition.Status == corev1.ConditionTrue && condition.Message != "" {
+			messages = append(messages, condition.Message)
+		}
+	}
+
+	return featurebranchv2.StuckNamespace{
+		Name:               namespace.Name,
+		TerminatingSince:   *namespace.DeletionTimestamp,
+		Finalizers:         sortedKeys(finalizers),
+		RemainingResources: sortedKeys(remainingResources),
+		Message:            strings.Join(messages, " "),
+	}, nil
+}
+
+// ClearLeftoverFinalizers removes finalizers of the namespace's well-known leftover resources that are already being
+// deleted, so the namespace's deletion is able to finish. Returns the number of cleared resources.
+func (r *ReconcileStaleFeatureBranch) ClearLeftoverFinalizers(namespace corev1.Namespace) (int, error) {
+	clearedResources := 0
+
+	for _, resource := range newLeftoverResources() {
+		objects, err := r.listLeftoverObjects(namespace, resource)
+
+		if err != nil {
+			return clearedResources, err
+		}
+
+		for _, object := range objects {
+			if object.GetDeletionTimestamp() == nil || len(object.GetFinalizers()) == 0 {
+				continue
+			}
+
+			original := object.(runtime.Object).DeepCopyObject()
+			object.SetFinalizers(nil)
+
+			if err := r.Client.Patch(context.TODO(), object.(runtime.Object), client.MergeFrom(original)); err != nil {
+				return clearedResources, err
+			}
+
+			logger.Info(
+				"Finalizers of a leftover resource have been cleared.",
+				"namespaceName", namespace.Name,
+				"resource", resource.name,
+				"resourceName", object.GetName(),
+			)
+
+			clearedResources++
+		}
+	}
+
+	return clearedResources, nil
+}
+
+// ProcessStuckNamespace reports the terminating namespace to the status and events if it's stuck terminating, and
+// clears finalizers of its leftover resources if it's enabled.
+func (r *ReconcileStaleFeatureBranch) ProcessStuckNamespace(staleFeatureBranch *featurebranchv2.StaleFeatureBranch, namespace corev1.Namespace) error {
+	threshold := staleFeatureBranch.Spec.StuckTerminatingAfter.Duration
+
+	if threshold == 0 {
+		threshold = featurebranchv2.DefaultStuckTerminatingAfter
+	}
+
+	if !IsNamespaceStuckTerminating(namespace, threshold, metav1.Now().Time) {
+		return nil
+	}
+
+	stuckNamespace, err := r.InspectStuckNamespace(namespace)
+
+	if err != nil {
+		return err
+	}
+
+	staleFeatureBranch.Status.StuckNamespaces = append(staleFeatureBranch.Status.StuckNamespaces, stuckNamespace)
+
+	logger.Info(
+		"Namespace is stuck terminating.",
+		"namespaceName", namespace.Name,
+		"finalizers", stuckNamespace.Finalizers,
+		"remainingResources", stuckNamespace.RemainingResources,
+	)
+
+	r.recordNamespaceEvent(
+		staleFeatureBranch,
+		&namespace,
+		corev1.EventTypeWarning,
+		StuckTerminatingEventReason,
+		"Namespace %s is stuck terminating since %s, finalizers: [%s], remaining resources: [%s].%s",
+		namespace.Name,
+		stuckNamespace.TerminatingSince.UTC().Format(time.RFC3339),
+		strings.Join(stuckNamespace.Finalizers, ", "),
+		strings.Join(stuckNamespace.RemainingResources, ", "),
+		formatEventMessage(stuckNamespace.Message),
+	)
+
+	if !staleFeatureBranch.Spec.ClearStuckFinalizers {
+		return nil
+	}
+
+	clearedResources, err := r.ClearLeftoverFinalizers(namespace)
+
+	if err != nil {
+		return err
+	}
+
+	if clearedResources > 0 {
+		r.recordNamespaceEvent(
+			staleFeatureBranch,
+			&namespace,
+			corev1.EventTypeNormal,
+			FinalizersClearedEventReason,
+			"Finalizers of %d leftover resources in namespace %s have been cleared.",
+			clearedResources,
+			namespace.Name,
+		)
+	}
+
+	return nil
+}
+
+// listLeftoverObjects lists the namespace's leftover objects of the resource from the API server, so the informer cache
+// doesn't start cluster-wide informers of secrets, pods and other kinds for lookups of a few stuck namespaces.
+func (r *ReconcileStaleFeatureBranch) listLeftoverObjects(namespace corev1.Namespace, resource leftoverResource) ([]metav1.Object, error) {
+	if err := r.getAPIReader().List(context.TODO(), resource.list, client.InNamespace(namespace.Name)); err != nil {
+		return nil, err
+	}
+
+	items, err := meta.ExtractList(resource.list)
+
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]metav1.Object, 0, len(items))
+
+	for _, item := range items {
+		object, err := meta.Accessor(item)
+
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, nil
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
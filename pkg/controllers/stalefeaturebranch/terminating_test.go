@@ -0,0 +1,218 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Case: delete stale feature branches.
+// Where: namespaces are already terminating, one of them is stuck by a persistent volume claim's finalizer.
+// Expected: terminating namespaces aren't deleted again, stuck namespace is reported and the finalizer is cleared.
+func TestReconcilerStaleFeatureBranchesTerminatingNamespaces(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace = "stale-feature-branch-operator"
+		stuckDeletionTimestamp      = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+		recentDeletionTimestamp     = metav1.NewTime(time.Now().Add(-time.Minute))
+		recorder                    = record.NewFakeRecorder(100)
+		reconciler                  ReconcileStaleFeatureBranch
+		request                     reconcile.Request
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring:    "-pr-",
+			StaleAfter:            metav1.Duration{Duration: 24 * time.Hour},
+			Interval:              metav1.Duration{Duration: time.Minute},
+			StuckTerminatingAfter: metav1.Duration{Duration: 30 * time.Minute},
+			ClearStuckFinalizers:  true,
+		},
+	}
+
+	stuckNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			DeletionTimestamp: &stuckDeletionTimestamp,
+		},
+		Spec: corev1.NamespaceSpec{
+			Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes},
+		},
+		Status: corev1.NamespaceStatus{
+			Phase: corev1.NamespaceTerminating,
+			Conditions: []corev1.NamespaceCondition{
+				{
+					Type:    corev1.NamespaceContentRemaining,
+					Status:  corev1.ConditionTrue,
+					Message: "Some resources are remaining: persistentvolumeclaims. has 1 resource instances",
+				},
+			},
+		},
+	}
+
+	recentlyTerminatingNamespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-2",
+			DeletionTimestamp: &recentDeletionTimestamp,
+		},
+	}
+
+	persistentVolumeClaim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "data",
+			Namespace:         "project-pr-1",
+			DeletionTimestamp: &stuckDeletionTimestamp,
+			Finalizers:        []string{"kubernetes.io/pvc-protection"},
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		stuckNamespace,
+		recentlyTerminatingNamespace,
+		persistentVolumeClaim,
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: recorder,
+	}
+
+	request = reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	_, err := reconciler.Reconcile(request)
+
+	if err != nil {
+		t.Fatalf("An error occurred while reconciling a stale feature branch: (%v)", err)
+	}
+
+	var updatedStaleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := reconciler.Client.Get(context.TODO(), request.NamespacedName, &updatedStaleFeatureBranch); err != nil {
+		t.Fatalf("An error occurred while fetching a stale feature branch: (%v)", err)
+	}
+
+	status := updatedStaleFeatureBranch.Status
+
+	assert.Equal(t, 0, status.DeletedNamespacesCount, "Terminating namespaces aren't counted as deleted.")
+	assert.Equal(t, 2, status.SkippedNamespacesCount, "Terminating namespaces are skipped.")
+	assert.Equal(t, TerminatingSkipReason, status.SkippedNamespaces[0].Reason, "Namespace is skipped as terminating.")
+	assert.Equal(t, 1, len(status.StuckNamespaces), "Only namespace terminating longer than the threshold is stuck.")
+
+	assert.Equal(
+		t,
+		featurebranchv2.StuckNamespace{
+			Name:               "project-pr-1",
+			TerminatingSince:   status.StuckNamespaces[0].TerminatingSince,
+			Finalizers:         []string{"kubernetes", "kubernetes.io/pvc-protection"},
+			RemainingResources: []string{"persistentvolumeclaims"},
+			Message:            "Some resources are remaining: persistentvolumeclaims. has 1 resource instances",
+		},
+		status.StuckNamespaces[0],
+		"Blocking finalizers and remaining resources are reported.",
+	)
+
+	var updatedPersistentVolumeClaim corev1.PersistentVolumeClaim
+
+	if err := reconciler.Client.Get(context.TODO(), types.NamespacedName{Namespace: "project-pr-1", Name: "data"}, &updatedPersistentVolumeClaim); err != nil {
+		t.Fatalf("An error occurred while fetching a persistent volume claim: (%v)", err)
+	}
+
+	assert.Empty(t, updatedPersistentVolumeClaim.Finalizers, "Leftover resource's finalizers are cleared.")
+
+	events := receiveEvents(recorder)
+
+	assert.Contains(
+		t,
+		events,
+		"Normal FinalizersCleared Finalizers of 1 leftover resources in namespace project-pr-1 have been cleared.",
+		"Cleared finalizers are recorded to events.",
+	)
+}
+
+// Case: check whether a namespace is stuck terminating.
+// Where: namespace is terminating for shorter, exactly and longer than the threshold.
+// Expected: namespace is stuck since the threshold.
+func TestIsNamespaceStuckTerminating(t *testing.T) {
+	// Set up data for tests.
+	var (
+		deletionTimestamp = metav1.Date(2010, time.January, 8, 12, 0, 0, 0, time.UTC)
+		namespace         = corev1.Namespace{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deletionTimestamp}}
+	)
+
+	// Testing.
+	assert.False(t, IsNamespaceStuckTerminating(namespace, time.Hour, deletionTimestamp.Add(59*time.Minute)), "Namespace isn't stuck yet.")
+	assert.True(t, IsNamespaceStuckTerminating(namespace, time.Hour, deletionTimestamp.Add(time.Hour)), "Namespace is stuck at the threshold.")
+	assert.False(t, IsNamespaceStuckTerminating(corev1.Namespace{}, time.Hour, deletionTimestamp.Add(time.Hour)), "Active namespace isn't stuck.")
+}
+
+// Case: inspect a stuck namespace's leftover resources.
+// Where: leftover pod is only known to the API server, the cached client doesn't have it.
+// Expected: leftover resources are listed from the API server.
+func TestInspectStuckNamespaceAPIReader(t *testing.T) {
+	// Set up data for tests.
+	namespace := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "project-pr-1",
+			DeletionTimestamp: &metav1.Time{Time: time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "project",
+			Namespace:  "project-pr-1",
+			Finalizers: []string{"example.com/protection"},
+		},
+	}
+
+	reconciler := ReconcileStaleFeatureBranch{
+		Client:    fake.NewFakeClientWithScheme(scheme.Scheme),
+		APIReader: fake.NewFakeClientWithScheme(scheme.Scheme, pod),
+		Scheme:    scheme.Scheme,
+	}
+
+	// Testing.
+	stuckNamespace, err := reconciler.InspectStuckNamespace(namespace)
+
+	if err != nil {
+		t.Fatalf("An error occurred while inspecting the stuck namespace: (%v)", err)
+	}
+
+	assert.Equal(t, []string{"pods"}, stuckNamespace.RemainingResources, "Leftover pod is listed from the API server.")
+	assert.Equal(t, []string{"example.com/protection"}, stuckNamespace.Finalizers, "Finalizer of the leftover pod is collected.")
+}
@@ -0,0 +1,100 @@
+package stalefeaturebranch
+
+import (
+	"sync"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// RunTriggers requests runs of stale feature branches before their schedules, e.g. by pull request events, and
+// enqueues their reconciles through the channels watched by the controllers.
+type RunTriggers struct {
+	StaleFeatureBranchEvents        chan event.GenericEvent
+	ClusterStaleFeatureBranchEvents chan event.GenericEvent
+
+	mutex     sync.Mutex
+	requested map[types.NamespacedName]bool
+}
+
+// NewRunTriggers creates the run triggers with buffered channels.
+func NewRunTriggers() *RunTriggers {
+	return &RunTriggers{
+		StaleFeatureBranchEvents:        make(chan event.GenericEvent, runTriggersBufferSize),
+		ClusterStaleFeatureBranchEvents: make(chan event.GenericEvent, runTriggersBufferSize),
+		requested:                       make(map[types.NamespacedName]bool),
+	}
+}
+
+// Trigger requests the stale feature branch's run and enqueues its reconcile. Returns false if the controller's queue
+// is full, then the stale feature branch runs by its schedule.
+func (t *RunTriggers) Trigger(staleFeatureBranch featurebranchv2.StaleFeatureBranch) bool {
+	t.Request(staleFeatureBranch)
+
+	events := t.StaleFeatureBranchEvents
+	genericEvent := event.GenericEvent{Meta: &staleFeatureBranch.ObjectMeta, Object: &staleFeatureBranch}
+
+	if IsClusterScoped(staleFeatureBranch) {
+		clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{ObjectMeta: staleFeatureBranch.ObjectMeta}
+
+		events = t.ClusterStaleFeatureBranchEvents
+		genericEvent = event.GenericEvent{Meta: &clusterStaleFeatureBranch.ObjectMeta, Object: clusterStaleFeatureBranch}
+	}
+
+	select {
+	case events <- genericEvent:
+		return true
+	default:
+		return false
+	}
+}
+
+// Request requests the stale feature branch's run without enqueuing its reconcile, e.g. if it's enqueued by a watch.
+// Nil triggers ignore requests.
+func (t *RunTriggers) Request(staleFeatureBranch featurebranchv2.StaleFeatureBranch) {
+	if t == nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.requested[getRunTriggerKey(staleFeatureBranch)] = true
+}
+
+// IsRequested reports whether the stale feature branch's run is requested without forgetting the request. Nil triggers
+// never request runs.
+func (t *RunTriggers) IsRequested(staleFeatureBranch featurebranchv2.StaleFeatureBranch) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.requested[getRunTriggerKey(staleFeatureBranch)]
+}
+
+// Take reports whether the stale feature branch's run is requested and forgets the request. Nil triggers never request
+// runs.
+func (t *RunTriggers) Take(staleFeatureBranch featurebranchv2.StaleFeatureBranch) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := getRunTriggerKey(staleFeatureBranch)
+	isRequested := t.requested[key]
+
+	delete(t.requested, key)
+
+	return isRequested
+}
+
+func getRunTriggerKey(staleFeatureBranch featurebranchv2.StaleFeatureBranch) types.NamespacedName {
+	return types.NamespacedName{Namespace: staleFeatureBranch.Namespace, Name: staleFeatureBranch.Name}
+}
@@ -0,0 +1,99 @@
+package stalefeaturebranch
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"bou.ke/monkey"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Case: delete stale feature branches.
+// Where: schedule is at 02:00 daily, the last run is done today at 02:00, the run is triggered at 12:00.
+// Expected: namespace is deleted regardless of the schedule, the trigger is forgotten after the run.
+func TestReconcilerStaleFeatureBranchesTriggered(t *testing.T) {
+	// Set up data for tests.
+	var (
+		staleFeatureBranchName      = "stale-feature-branch-operator"
+		staleFeatureBranchNamespace = "stale-feature-branch-operator"
+		lastRunTime                 = metav1.Date(2010, time.January, 10, 2, 0, 0, 0, time.UTC)
+		currentTimestamp            = time.Date(2010, time.January, 10, 12, 0, 0, 0, time.UTC)
+		triggers                    = NewRunTriggers()
+		reconciler                  ReconcileStaleFeatureBranch
+	)
+
+	if err := os.Setenv("IS_DEBUG", "true"); err != nil {
+		t.Fatalf("An error occurred while enabling debug: (%v)", err)
+	}
+
+	defer os.Setenv("IS_DEBUG", "false")
+
+	patch := monkey.Patch(time.Now, func() time.Time { return currentTimestamp })
+	defer patch.Unpatch()
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "-pr-",
+			StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+			Schedule:           "0 2 * * *",
+		},
+		Status: featurebranchv2.StaleFeatureBranchStatus{
+			LastRunTime: &lastRunTime,
+		},
+	}
+
+	objects := []runtime.Object{
+		staleFeatureBranch,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "project-pr-1"}},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch)
+
+	reconciler = ReconcileStaleFeatureBranch{
+		Client:   fake.NewFakeClientWithScheme(s, ownNamespaces(staleFeatureBranch.Namespace, objects)...),
+		Scheme:   s,
+		Recorder: record.NewFakeRecorder(100),
+		Triggers: triggers,
+	}
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      staleFeatureBranchName,
+			Namespace: staleFeatureBranchNamespace,
+		},
+	}
+
+	// Testing.
+	assert.True(t, triggers.Trigger(*staleFeatureBranch), "Reconcile is enqueued.")
+	assert.Len(t, triggers.StaleFeatureBranchEvents, 1, "Reconcile is enqueued to the stale feature branches' controller.")
+
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("An error occurred while calling the reconcile with a request: (%v)", err)
+	}
+
+	var allNamespaces corev1.NamespaceList
+
+	if err := reconciler.Client.List(context.TODO(), &allNamespaces); err != nil {
+		t.Fatalf("An error occurred while fetching all namespaces: (%v)", err)
+	}
+
+	assert.Empty(t, allNamespaces.Items, "Namespace is deleted as the run is triggered.")
+	assert.False(t, triggers.Take(*staleFeatureBranch), "Trigger is forgotten after the run.")
+}
@@ -0,0 +1,157 @@
+package stalefeaturebranch
+
+import (
+	"context"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// NamespaceMapper maps events of namespaces to stale feature branches matching them and requests their runs, so new
+// namespaces and changes of namespaces' labels and annotations are processed without waiting for schedules. Events
+// invalidate the namespace batch's pass, so the runs see the changes. Deleted and terminating namespaces only invalidate
+// it, so no run deletes them twice.
+type NamespaceMapper struct {
+	Client          client.Client
+	Triggers        *RunTriggers
+	Batch           *NamespaceBatch
+	IsClusterScoped bool
+}
+
+var _ handler.Mapper = &NamespaceMapper{}
+
+func (m *NamespaceMapper) Map(object handler.MapObject) []reconcile.Request {
+	namespace, isNamespace := object.Object.(*corev1.Namespace)
+
+	if !isNamespace {
+		return nil
+	}
+
+	m.Batch.Invalidate()
+
+	if IsNamespaceTerminating(*namespace) {
+		return nil
+	}
+
+	staleFeatureBranches, err := m.listStaleFeatureBranches()
+
+	if err != nil {
+		logger.Error(err, "Unable to fetch stale feature branches of a namespace.", "namespaceName", namespace.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+
+	for _, staleFeatureBranch := range staleFeatureBranches {
+		matcher, err := NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+		if err == nil && !IsClusterScoped(staleFeatureBranch) {
+			err = matcher.RestrictToOwner(staleFeatureBranch.Namespace)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if _, isMatched := matcher.Match(*namespace); !isMatched {
+			continue
+		}
+
+		// Cron schedule's window is honoured, so its run is only enqueued to recompute the time of the next run.
+		if staleFeatureBranch.Spec.Schedule == "" {
+			m.Triggers.Request(staleFeatureBranch)
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: getRunTriggerKey(staleFeatureBranch)})
+	}
+
+	return requests
+}
+
+func (m *NamespaceMapper) listStaleFeatureBranches() ([]featurebranchv2.StaleFeatureBranch, error) {
+	if !m.IsClusterScoped {
+		var staleFeatureBranches featurebranchv2.StaleFeatureBranchList
+
+		if err := m.Client.List(context.TODO(), &staleFeatureBranches); err != nil {
+			return nil, err
+		}
+
+		return staleFeatureBranches.Items, nil
+	}
+
+	var clusterStaleFeatureBranches featurebranchv2.ClusterStaleFeatureBranchList
+
+	if err := m.Client.List(context.TODO(), &clusterStaleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	staleFeatureBranches := make([]featurebranchv2.StaleFeatureBranch, 0, len(clusterStaleFeatureBranches.Items))
+
+	for _, clusterStaleFeatureBranch := range clusterStaleFeatureBranches.Items {
+		staleFeatureBranches = append(staleFeatureBranches, NewClusterScopedStaleFeatureBranch(clusterStaleFeatureBranch))
+	}
+
+	return staleFeatureBranches, nil
+}
+
+// operatorOwnedNamespaceKeys are labels and annotations the operator sets to namespaces itself. Their changes are made
+// by runs, so they don't request more runs.
+var operatorOwnedNamespaceKeys = map[string]bool{
+	featurebranch.MarkedForDeletionAtAnnotation: true,
+	featurebranch.MarkedForDeletionLabel:        true,
+	featurebranch.RescuedAtAnnotation:           true,
+	featurebranch.HibernatedAtAnnotation:        true,
+	featurebranch.WokenAtAnnotation:             true,
+}
+
+// NewNamespaceChangedPredicate filters events of namespaces to created, deleted and terminating ones and ones with
+// changed labels or annotations. Changes made by the operator itself (marks, rescues, hibernation and waking) are
+// skipped unless a developer rescues the namespace by removing the mark.
+func NewNamespaceChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(updateEvent event.UpdateEvent) bool {
+			if updateEvent.MetaOld == nil || updateEvent.MetaNew == nil {
+				return false
+			}
+
+			if updateEvent.MetaOld.GetDeletionTimestamp() == nil && updateEvent.MetaNew.GetDeletionTimestamp() != nil {
+				return true
+			}
+
+			oldNamespace, isOldNamespace := updateEvent.ObjectOld.(*corev1.Namespace)
+			newNamespace, isNewNamespace := updateEvent.ObjectNew.(*corev1.Namespace)
+
+			if isOldNamespace && isNewNamespace && !IsNamespaceRescued(*oldNamespace) && IsNamespaceRescued(*newNamespace) {
+				return true
+			}
+
+			return isChangedByDeveloper(updateEvent.MetaOld.GetLabels(), updateEvent.MetaNew.GetLabels()) ||
+				isChangedByDeveloper(updateEvent.MetaOld.GetAnnotations(), updateEvent.MetaNew.GetAnnotations())
+		},
+	}
+}
+
+// isChangedByDeveloper reports whether labels or annotations are changed by keys other than the operator's own ones.
+// Removal of the wake annotation is the operator's too, as it's removed once the namespace is woken.
+func isChangedByDeveloper(oldValues map[string]string, newValues map[string]string) bool {
+	for key, newValue := range newValues {
+		if oldValue, isOld := oldValues[key]; (!isOld || oldValue != newValue) && !operatorOwnedNamespaceKeys[key] {
+			return true
+		}
+	}
+
+	for key := range oldValues {
+		if _, isNew := newValues[key]; !isNew && !operatorOwnedNamespaceKeys[key] && key != featurebranch.WakeAnnotation {
+			return true
+		}
+	}
+
+	return false
+}
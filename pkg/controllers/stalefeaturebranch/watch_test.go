@@ -0,0 +1,247 @@
+package stalefeaturebranch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Case: map an event of a namespace to stale feature branches.
+// Where: one stale feature branch matches the namespace, another one matches other namespaces, the third one matches
+// it by the substring, but it's in another namespace than the namespace's owner.
+// Expected: only the matching stale feature branch of the namespace's owner is enqueued and its run is requested.
+func TestNamespaceMapperMap(t *testing.T) {
+	// Set up data for tests.
+	newStaleFeatureBranch := func(namespace string, name string, namespaceSubstring string) *featurebranchv2.StaleFeatureBranch {
+		return &featurebranchv2.StaleFeatureBranch{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: featurebranchv2.StaleFeatureBranchSpec{
+				NamespaceSubstring: namespaceSubstring,
+				StaleAfter:         metav1.Duration{Duration: time.Hour},
+			},
+		}
+	}
+
+	backEnd := newStaleFeatureBranch("back-end", "stale-feature-branch", "back-end-pr-")
+	frontEnd := newStaleFeatureBranch("front-end", "stale-feature-branch", "front-end-pr-")
+	foreign := newStaleFeatureBranch("front-end", "foreign-stale-feature-branch", "back-end-pr-")
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "back-end-pr-1",
+			Labels: map[string]string{
+				featurebranch.OwnerLabel: "back-end",
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, backEnd, &featurebranchv2.StaleFeatureBranchList{})
+
+	triggers := NewRunTriggers()
+
+	mapper := NamespaceMapper{
+		Client:   fake.NewFakeClientWithScheme(s, []runtime.Object{backEnd, frontEnd, foreign}...),
+		Triggers: triggers,
+	}
+
+	// Testing.
+	requests := mapper.Map(handler.MapObject{Meta: namespace, Object: namespace})
+
+	assert.Equal(
+		t,
+		[]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "back-end", Name: "stale-feature-branch"}}},
+		requests,
+		"Only the matching stale feature branch of the namespace's owner is enqueued.",
+	)
+
+	assert.True(t, triggers.Take(*backEnd), "Run of the matching stale feature branch is requested.")
+	assert.False(t, triggers.Take(*foreign), "Run of the foreign stale feature branch isn't requested.")
+	assert.Empty(t, triggers.StaleFeatureBranchEvents, "Watch enqueues the reconcile itself, triggers don't.")
+}
+
+// Case: map an event of a deleted namespace to stale feature branches.
+// Where: namespace batch has a pass of the matching stale feature branch, the namespace is being deleted.
+// Expected: pass is invalidated, nothing is enqueued and runs aren't requested.
+func TestNamespaceMapperMapDeleted(t *testing.T) {
+	// Set up data for tests.
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "back-end",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "back-end-pr-",
+			StaleAfter:         metav1.Duration{Duration: time.Hour},
+		},
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "back-end-pr-1",
+			Labels:            map[string]string{featurebranch.OwnerLabel: "back-end"},
+			DeletionTimestamp: &metav1.Time{Time: time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch, &featurebranchv2.StaleFeatureBranchList{})
+
+	triggers := NewRunTriggers()
+	batch := &NamespaceBatch{results: map[types.NamespacedName]namespaceBatchResult{getRunTriggerKey(*staleFeatureBranch): {}}}
+
+	mapper := NamespaceMapper{
+		Client:   fake.NewFakeClientWithScheme(s, []runtime.Object{staleFeatureBranch}...),
+		Triggers: triggers,
+		Batch:    batch,
+	}
+
+	// Testing.
+	requests := mapper.Map(handler.MapObject{Meta: namespace, Object: namespace})
+
+	assert.Empty(t, requests, "Nothing is enqueued by the deleted namespace.")
+	assert.Empty(t, batch.results, "Pass of the namespace batch is invalidated.")
+	assert.False(t, triggers.Take(*staleFeatureBranch), "Run isn't requested by the deleted namespace.")
+}
+
+// Case: map an event of a namespace to a stale feature branch with a cron schedule.
+// Where: stale feature branch matches the namespace and runs by the cron schedule.
+// Expected: stale feature branch is enqueued, but its run isn't requested out of the schedule's window.
+func TestNamespaceMapperMapCronSchedule(t *testing.T) {
+	// Set up data for tests.
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "stale-feature-branch",
+			Namespace: "back-end",
+		},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring: "back-end-pr-",
+			StaleAfter:         metav1.Duration{Duration: time.Hour},
+			Schedule:           "0 2 * * 1-5",
+		},
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "back-end-pr-1",
+			Labels: map[string]string{
+				featurebranch.OwnerLabel: "back-end",
+			},
+		},
+	}
+
+	s := scheme.Scheme
+	s.AddKnownTypes(featurebranchv2.SchemeGroupVersion, staleFeatureBranch, &featurebranchv2.StaleFeatureBranchList{})
+
+	triggers := NewRunTriggers()
+
+	mapper := NamespaceMapper{
+		Client:   fake.NewFakeClientWithScheme(s, []runtime.Object{staleFeatureBranch}...),
+		Triggers: triggers,
+	}
+
+	// Testing.
+	requests := mapper.Map(handler.MapObject{Meta: namespace, Object: namespace})
+
+	assert.Equal(
+		t,
+		[]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "back-end", Name: "stale-feature-branch"}}},
+		requests,
+		"Stale feature branch with the cron schedule is enqueued.",
+	)
+
+	assert.False(t, triggers.Take(*staleFeatureBranch), "Run out of the cron schedule's window isn't requested.")
+}
+
+// Case: filter events of namespaces.
+// Where: namespace is created, its labels, annotations or status are changed by a developer or the operator, or it's
+// deleted.
+// Expected: creations, deletions and developers' changes of labels and annotations are processed.
+func TestNewNamespaceChangedPredicate(t *testing.T) {
+	// Set up data for tests.
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "back-end-pr-1",
+			Labels:      map[string]string{"team": "back-end"},
+			Annotations: map[string]string{featurebranch.KeepAnnotation: "false"},
+		},
+	}
+
+	relabeledNamespace := namespace.DeepCopy()
+	relabeledNamespace.Labels["team"] = "front-end"
+
+	reannotatedNamespace := namespace.DeepCopy()
+	reannotatedNamespace.Annotations[featurebranch.KeepAnnotation] = "true"
+
+	terminatingNamespace := namespace.DeepCopy()
+	terminatingNamespace.Status.Phase = corev1.NamespaceTerminating
+
+	deletedNamespace := namespace.DeepCopy()
+	deletedNamespace.DeletionTimestamp = &metav1.Time{Time: time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC)}
+
+	markedNamespace := namespace.DeepCopy()
+	markedNamespace.Labels[featurebranch.MarkedForDeletionLabel] = "true"
+	markedNamespace.Annotations[featurebranch.MarkedForDeletionAtAnnotation] = "2010-01-01T00:00:00Z"
+
+	rescuedNamespace := markedNamespace.DeepCopy()
+	delete(rescuedNamespace.Annotations, featurebranch.MarkedForDeletionAtAnnotation)
+
+	operatorRescuedNamespace := markedNamespace.DeepCopy()
+	delete(operatorRescuedNamespace.Labels, featurebranch.MarkedForDeletionLabel)
+	delete(operatorRescuedNamespace.Annotations, featurebranch.MarkedForDeletionAtAnnotation)
+	operatorRescuedNamespace.Annotations[featurebranch.RescuedAtAnnotation] = "2010-01-01T01:00:00Z"
+
+	wakeNamespace := namespace.DeepCopy()
+	wakeNamespace.Annotations[featurebranch.HibernatedAtAnnotation] = "2010-01-01T00:00:00Z"
+	wakeNamespace.Annotations[featurebranch.WakeAnnotation] = "true"
+
+	wokenNamespace := namespace.DeepCopy()
+	wokenNamespace.Annotations[featurebranch.WokenAtAnnotation] = "2010-01-01T01:00:00Z"
+
+	changedPredicate := NewNamespaceChangedPredicate()
+
+	updateFrom := func(oldNamespace *corev1.Namespace, newNamespace *corev1.Namespace) event.UpdateEvent {
+		return event.UpdateEvent{MetaOld: oldNamespace, ObjectOld: oldNamespace, MetaNew: newNamespace, ObjectNew: newNamespace}
+	}
+
+	update := func(newNamespace *corev1.Namespace) event.UpdateEvent {
+		return updateFrom(namespace, newNamespace)
+	}
+
+	// Testing.
+	assert.True(t, changedPredicate.Create(event.CreateEvent{Meta: namespace, Object: namespace}), "Creation is processed.")
+	assert.True(t, changedPredicate.Update(update(relabeledNamespace)), "Labels' change is processed.")
+	assert.True(t, changedPredicate.Update(update(reannotatedNamespace)), "Annotations' change is processed.")
+	assert.False(t, changedPredicate.Update(update(terminatingNamespace)), "Status' change isn't processed.")
+	assert.True(t, changedPredicate.Update(update(deletedNamespace)), "Deletion's start is processed.")
+	assert.False(t, changedPredicate.Update(update(markedNamespace)), "Operator's mark isn't processed.")
+	assert.False(
+		t,
+		changedPredicate.Update(updateFrom(markedNamespace, operatorRescuedNamespace)),
+		"Operator's rescue isn't processed.",
+	)
+	assert.True(
+		t,
+		changedPredicate.Update(updateFrom(markedNamespace, rescuedNamespace)),
+		"Developer's rescue by the removed mark is processed.",
+	)
+	assert.True(t, changedPredicate.Update(update(wakeNamespace)), "Developer's wake request is processed.")
+	assert.False(t, changedPredicate.Update(updateFrom(wakeNamespace, wokenNamespace)), "Operator's waking isn't processed.")
+	assert.True(t, changedPredicate.Delete(event.DeleteEvent{Meta: namespace, Object: namespace}), "Deletion is processed.")
+}
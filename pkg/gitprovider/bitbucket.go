@@ -0,0 +1,60 @@
+package gitprovider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// BitbucketProvider gets states of pull requests from Bitbucket Cloud REST API. Username with an app password are
+// sent by basic authentication, token without a username is sent as a bearer token, e.g. a repository access token.
+type BitbucketProvider struct {
+	BaseURL    string
+	Username   string
+	Token      string
+	HTTPClient *http.Client
+}
+
+var _ Provider = &BitbucketProvider{}
+
+// bitbucketPullRequest is a part of Bitbucket's pull request
+type bitbucketPullRequest struct {
+	State string `json:"state"`
+}
+
+func (p *BitbucketProvider) GetPullRequestState(repository string, number int) (PullRequestState, error) {
+	url := fmt.Sprintf(
+		"%s/repositories/%s/pullrequests/%d",
+		strings.TrimSuffix(baseURL(p.BaseURL, DefaultBitbucketURL), "/"),
+		repository,
+		number,
+	)
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case p.Username != "":
+		request.SetBasicAuth(p.Username, p.Token)
+	case p.Token != "":
+		request.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	var pullRequest bitbucketPullRequest
+
+	if err := getJSON(p.HTTPClient, request, &pullRequest); err != nil {
+		return "", err
+	}
+
+	switch pullRequest.State {
+	case bitbucketOpenState:
+		return OpenPullRequestState, nil
+	case bitbucketMergedState:
+		return MergedPullRequestState, nil
+	default:
+		return ClosedPullRequestState, nil
+	}
+}
@@ -0,0 +1,21 @@
+package gitprovider
+
+const (
+	// DefaultGitHubURL is the base URL of GitHub REST API
+	DefaultGitHubURL = "https://api.github.com"
+	// DefaultGitLabURL is the base URL of GitLab REST API
+	DefaultGitLabURL = "https://gitlab.com/api/v4"
+	// DefaultBitbucketURL is the base URL of Bitbucket Cloud REST API
+	DefaultBitbucketURL = "https://api.bitbucket.org/2.0"
+)
+
+const (
+	gitHubOpenState = "open"
+
+	gitLabOpenedState = "opened"
+	gitLabMergedState = "merged"
+	gitLabLockedState = "locked"
+
+	bitbucketOpenState   = "OPEN"
+	bitbucketMergedState = "MERGED"
+)
@@ -0,0 +1,52 @@
+package gitprovider
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GitHubProvider gets states of pull requests from GitHub or GitHub Enterprise REST API. Token is a personal access
+// token or an installation token of a GitHub App, it's optional for public repositories.
+type GitHubProvider struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+var _ Provider = &GitHubProvider{}
+
+// gitHubPullRequest is a part of GitHub's pull request
+type gitHubPullRequest struct {
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+}
+
+func (p *GitHubProvider) GetPullRequestState(repository string, number int) (PullRequestState, error) {
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d", strings.TrimSuffix(baseURL(p.BaseURL, DefaultGitHubURL), "/"), repository, number)
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	if p.Token != "" {
+		request.Header.Set("Authorization", "token "+p.Token)
+	}
+
+	var pullRequest gitHubPullRequest
+
+	if err := getJSON(p.HTTPClient, request, &pullRequest); err != nil {
+		return "", err
+	}
+
+	switch {
+	case pullRequest.Merged:
+		return MergedPullRequestState, nil
+	case pullRequest.State == gitHubOpenState:
+		return OpenPullRequestState, nil
+	default:
+		return ClosedPullRequestState, nil
+	}
+}
@@ -0,0 +1,57 @@
+package gitprovider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GitLabProvider gets states of merge requests from GitLab REST API. Token is a personal, project or group access
+// token, it's optional for public projects.
+type GitLabProvider struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+var _ Provider = &GitLabProvider{}
+
+// gitLabMergeRequest is a part of GitLab's merge request
+type gitLabMergeRequest struct {
+	State string `json:"state"`
+}
+
+func (p *GitLabProvider) GetPullRequestState(repository string, number int) (PullRequestState, error) {
+	requestURL := fmt.Sprintf(
+		"%s/projects/%s/merge_requests/%d",
+		strings.TrimSuffix(baseURL(p.BaseURL, DefaultGitLabURL), "/"),
+		url.PathEscape(repository),
+		number,
+	)
+
+	request, err := http.NewRequest(http.MethodGet, requestURL, nil)
+
+	if err != nil {
+		return "", err
+	}
+
+	if p.Token != "" {
+		request.Header.Set("PRIVATE-TOKEN", p.Token)
+	}
+
+	var mergeRequest gitLabMergeRequest
+
+	if err := getJSON(p.HTTPClient, request, &mergeRequest); err != nil {
+		return "", err
+	}
+
+	switch mergeRequest.State {
+	case gitLabOpenedState, gitLabLockedState:
+		return OpenPullRequestState, nil
+	case gitLabMergedState:
+		return MergedPullRequestState, nil
+	default:
+		return ClosedPullRequestState, nil
+	}
+}
@@ -0,0 +1,74 @@
+package gitprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// PullRequestState is a state of a pull request (merge request of GitLab)
+type PullRequestState string
+
+const (
+	// OpenPullRequestState means a pull request is still in review
+	OpenPullRequestState PullRequestState = "Open"
+	// ClosedPullRequestState means a pull request is closed without merging
+	ClosedPullRequestState PullRequestState = "Closed"
+	// MergedPullRequestState means a pull request is merged
+	MergedPullRequestState PullRequestState = "Merged"
+)
+
+// IsFinished reports whether the pull request is closed or merged, so its feature branch isn't needed anymore.
+func (s PullRequestState) IsFinished() bool {
+	return s == ClosedPullRequestState || s == MergedPullRequestState
+}
+
+// Provider gets states of pull requests from a Git hosting provider.
+type Provider interface {
+	// GetPullRequestState returns the state of the repository's pull request by its number. Repository is a full path,
+	// e.g. owner/name of GitHub, group/subgroup/project of GitLab or workspace/slug of Bitbucket.
+	GetPullRequestState(repository string, number int) (PullRequestState, error)
+}
+
+// getJSON sends the request and decodes the successful response's JSON body.
+func getJSON(httpClient *http.Client, request *http.Request, body interface{}) error {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	response, err := httpClient.Do(request)
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s: %s: %s", request.Method, request.URL, response.Status, responseBody)
+	}
+
+	if err := json.Unmarshal(responseBody, body); err != nil {
+		return fmt.Errorf("invalid response of %s %s: %v", request.Method, request.URL, err)
+	}
+
+	return nil
+}
+
+// baseURL returns the configured base URL or the provider's default one.
+func baseURL(configured string, defaultURL string) string {
+	if configured == "" {
+		return defaultURL
+	}
+
+	return configured
+}
@@ -0,0 +1,140 @@
+package gitprovider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gitProviderStandIn is a local stand-in for a Git hosting provider responding with pull requests by their paths.
+type gitProviderStandIn struct {
+	*httptest.Server
+
+	authorizations []string
+}
+
+func newGitProviderStandIn(pullRequests map[string]string) *gitProviderStandIn {
+	standIn := &gitProviderStandIn{}
+
+	standIn.Server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		standIn.authorizations = append(
+			standIn.authorizations, request.Header.Get("Authorization")+request.Header.Get("PRIVATE-TOKEN"),
+		)
+
+		pullRequest, isFound := pullRequests[request.URL.EscapedPath()]
+
+		if !isFound {
+			http.NotFound(writer, request)
+			return
+		}
+
+		fmt.Fprint(writer, pullRequest)
+	}))
+
+	return standIn
+}
+
+// Case: get states of GitHub's pull requests.
+// Where: local stand-in for GitHub responds with open, merged and closed pull requests.
+// Expected: states are mapped, the token is sent, unknown pull request is an error.
+func TestGitHubProvider(t *testing.T) {
+	// Set up data for tests.
+	standIn := newGitProviderStandIn(map[string]string{
+		"/repos/owner/back-end/pulls/1": `{"state": "open", "merged": false}`,
+		"/repos/owner/back-end/pulls/2": `{"state": "closed", "merged": true}`,
+		"/repos/owner/back-end/pulls/3": `{"state": "closed", "merged": false}`,
+	})
+
+	defer standIn.Close()
+
+	provider := GitHubProvider{BaseURL: standIn.URL, Token: "secret"}
+
+	// Testing.
+	for number, expectedState := range map[int]PullRequestState{
+		1: OpenPullRequestState,
+		2: MergedPullRequestState,
+		3: ClosedPullRequestState,
+	} {
+		state, err := provider.GetPullRequestState("owner/back-end", number)
+
+		if err != nil {
+			t.Fatalf("An error occurred while getting the pull request's state: (%v)", err)
+		}
+
+		assert.Equal(t, expectedState, state, fmt.Sprintf("Pull request #%d is %s.", number, expectedState))
+	}
+
+	assert.Equal(t, "token secret", standIn.authorizations[0], "Token is sent.")
+
+	_, err := provider.GetPullRequestState("owner/back-end", 4)
+	assert.Error(t, err, "Unknown pull request is an error.")
+}
+
+// Case: get states of GitLab's merge requests.
+// Where: local stand-in for GitLab responds with opened, merged and closed merge requests of a project in a subgroup.
+// Expected: states are mapped, the project's path is escaped, the token is sent.
+func TestGitLabProvider(t *testing.T) {
+	// Set up data for tests.
+	standIn := newGitProviderStandIn(map[string]string{
+		"/projects/group%2Fteam%2Fback-end/merge_requests/1": `{"state": "opened"}`,
+		"/projects/group%2Fteam%2Fback-end/merge_requests/2": `{"state": "merged"}`,
+		"/projects/group%2Fteam%2Fback-end/merge_requests/3": `{"state": "closed"}`,
+	})
+
+	defer standIn.Close()
+
+	provider := GitLabProvider{BaseURL: standIn.URL, Token: "secret"}
+
+	// Testing.
+	for number, expectedState := range map[int]PullRequestState{
+		1: OpenPullRequestState,
+		2: MergedPullRequestState,
+		3: ClosedPullRequestState,
+	} {
+		state, err := provider.GetPullRequestState("group/team/back-end", number)
+
+		if err != nil {
+			t.Fatalf("An error occurred while getting the merge request's state: (%v)", err)
+		}
+
+		assert.Equal(t, expectedState, state, fmt.Sprintf("Merge request !%d is %s.", number, expectedState))
+	}
+
+	assert.Equal(t, "secret", standIn.authorizations[0], "Token is sent.")
+}
+
+// Case: get states of Bitbucket's pull requests.
+// Where: local stand-in for Bitbucket responds with open, merged and declined pull requests.
+// Expected: states are mapped, the username with the app password are sent by basic authentication.
+func TestBitbucketProvider(t *testing.T) {
+	// Set up data for tests.
+	standIn := newGitProviderStandIn(map[string]string{
+		"/repositories/workspace/back-end/pullrequests/1": `{"state": "OPEN"}`,
+		"/repositories/workspace/back-end/pullrequests/2": `{"state": "MERGED"}`,
+		"/repositories/workspace/back-end/pullrequests/3": `{"state": "DECLINED"}`,
+	})
+
+	defer standIn.Close()
+
+	provider := BitbucketProvider{BaseURL: standIn.URL, Username: "user", Token: "secret"}
+
+	// Testing.
+	for number, expectedState := range map[int]PullRequestState{
+		1: OpenPullRequestState,
+		2: MergedPullRequestState,
+		3: ClosedPullRequestState,
+	} {
+		state, err := provider.GetPullRequestState("workspace/back-end", number)
+
+		if err != nil {
+			t.Fatalf("An error occurred while getting the pull request's state: (%v)", err)
+		}
+
+		assert.Equal(t, expectedState, state, fmt.Sprintf("Pull request #%d is %s.", number, expectedState))
+	}
+
+	assert.Equal(t, "Basic dXNlcjpzZWNyZXQ=", standIn.authorizations[0], "Username and app password are sent.")
+}
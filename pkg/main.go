@@ -7,6 +7,9 @@ import (
 
 	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis"
 	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers/stalefeaturebranch"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/receiver"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/webhooks"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
 	"github.com/operator-framework/operator-sdk/pkg/log/zap"
 	sdkVersion "github.com/operator-framework/operator-sdk/version"
@@ -22,6 +25,16 @@ var logger = logf.Log.WithName("main")
 func main() {
 	logf.SetLogger(zap.Logger())
 
+	if len(os.Args) > 1 && os.Args[1] == RestoreCommand {
+		if err := Restore(os.Args[2:]); err != nil {
+			logger.Error(err, "Error occurred while restoring a backup bundle.")
+			os.Exit(FailedExitCode)
+		}
+
+		logger.Info("Backup bundle is restored.")
+		return
+	}
+
 	logger.Info(
 		"System information is fetched.",
 		"Operator version", GetProjectVersion(),
@@ -45,9 +58,25 @@ func main() {
 		os.Exit(FailedExitCode)
 	}
 
-	mgr, err := manager.New(cfg, manager.Options{
-		Namespace: WatchAllNamespaces,
-	})
+	metricsBindAddress := os.Getenv("METRICS_BIND_ADDRESS")
+
+	if metricsBindAddress == "" {
+		metricsBindAddress = DefaultMetricsBindAddress
+	}
+
+	isWebhooksEnabled := os.Getenv("IS_WEBHOOKS_ENABLED") == "true"
+
+	options := manager.Options{
+		Namespace:          WatchAllNamespaces,
+		MetricsBindAddress: metricsBindAddress,
+	}
+
+	if isWebhooksEnabled {
+		options.Port = WebhookPort
+		options.CertDir = os.Getenv("WEBHOOK_CERT_DIR")
+	}
+
+	mgr, err := manager.New(cfg, options)
 
 	if err != nil {
 		logger.Error(err, "Error occurred while initialization of manager.")
@@ -59,11 +88,26 @@ func main() {
 		os.Exit(FailedExitCode)
 	}
 
-	if err := controllers.RegisterControllers(mgr); err != nil {
+	triggers := stalefeaturebranch.NewRunTriggers()
+	pullRequestStates := stalefeaturebranch.NewPullRequestStateCache()
+
+	if err := controllers.RegisterControllers(mgr, triggers, pullRequestStates); err != nil {
 		logger.Error(err, "Error occurred while registering controllers.")
 		os.Exit(FailedExitCode)
 	}
 
+	if err := receiver.RegisterReceiver(mgr, triggers, pullRequestStates); err != nil {
+		logger.Error(err, "Error occurred while registering pull request events receiver.")
+		os.Exit(FailedExitCode)
+	}
+
+	if isWebhooksEnabled {
+		if err := webhooks.RegisterWebhooks(mgr); err != nil {
+			logger.Error(err, "Error occurred while registering webhooks.")
+			os.Exit(FailedExitCode)
+		}
+	}
+
 	if err := mgr.Start(signals.SetupSignalHandler()); err != nil {
 		logger.Error(err, "Manager exited with error.")
 		os.Exit(FailedExitCode)
@@ -0,0 +1,22 @@
+package notification
+
+const (
+	// DefaultWarningTemplate is used if a stale feature branch doesn't customize warnings
+	DefaultWarningTemplate = `{{ range $index, $namespace := .Namespaces }}{{ if $index }}` + "\n" + `{{ end }}` +
+		`Namespace {{ .Name }}` +
+		`{{ with .PullRequestNumber }} of pull request #{{ . }}{{ end }} is {{ .Age }} old and will be deleted in ` +
+		`{{ .DeleteIn }} by {{ $.StaleFeatureBranch }}.{{ end }}`
+	// DefaultDeletedTemplate is used if a stale feature branch doesn't customize summaries of deleted namespaces
+	DefaultDeletedTemplate = `{{ len .Namespaces }} namespaces have been deleted by {{ .StaleFeatureBranch }}:` +
+		`{{ range .Namespaces }}` + "\n" + `- {{ .Name }}{{ with .PullRequestNumber }} of pull request #{{ . }}{{ end }}, ` +
+		`{{ .Age }} old{{ end }}`
+)
+
+const (
+	warningSubject = "Feature branches' namespaces will be deleted"
+	deletedSubject = "Feature branches' namespaces have been deleted"
+)
+
+const (
+	hoursInDay = 24
+)
@@ -0,0 +1,113 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Kind is a kind of notifications
+type Kind string
+
+const (
+	// WarningKind warns that namespaces marked for deletion will be deleted after the grace period
+	WarningKind Kind = "Warning"
+	// DeletedKind summarizes namespaces deleted by a run
+	DeletedKind Kind = "Deleted"
+)
+
+// Duration is a duration formatted for humans in days, hours and minutes, e.g. 3d4h.
+type Duration time.Duration
+
+func (d Duration) String() string {
+	minutes := int(time.Duration(d).Round(time.Minute).Minutes())
+
+	if minutes < 1 {
+		return "0m"
+	}
+
+	days := minutes / (hoursInDay * 60)
+	hours := minutes / 60 % hoursInDay
+	minutes = minutes % 60
+
+	var formatted strings.Builder
+
+	if days > 0 {
+		fmt.Fprintf(&formatted, "%dd", days)
+	}
+
+	if hours > 0 {
+		fmt.Fprintf(&formatted, "%dh", hours)
+	}
+
+	if minutes > 0 && days == 0 {
+		fmt.Fprintf(&formatted, "%dm", minutes)
+	}
+
+	return formatted.String()
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%q", d.String())), nil
+}
+
+// Namespace is a feature branch's namespace the notification is about.
+type Namespace struct {
+	Name              string   `json:"name"`
+	Age               Duration `json:"age"`
+	PullRequestNumber int      `json:"pullRequestNumber,omitempty"`
+	DeleteIn          Duration `json:"deleteIn,omitempty"`
+}
+
+// Notification is a message about feature branches' namespaces of a stale feature branch. Text is rendered by the
+// template of the notification's kind.
+type Notification struct {
+	Kind               Kind        `json:"kind"`
+	StaleFeatureBranch string      `json:"staleFeatureBranch"`
+	Namespaces         []Namespace `json:"namespaces"`
+	Subject            string      `json:"subject"`
+	Text               string      `json:"text"`
+}
+
+// NewTemplate parses the notification's template. Templates are Go templates with the notification as data.
+func NewTemplate(text string) (*template.Template, error) {
+	return template.New("notification").Parse(text)
+}
+
+// Render renders the notification's subject and text by the template of its kind. Default templates are used if the
+// templates are empty.
+func (n *Notification) Render(warningTemplate string, deletedTemplate string) error {
+	text := deletedTemplate
+	n.Subject = deletedSubject
+
+	if text == "" {
+		text = DefaultDeletedTemplate
+	}
+
+	if n.Kind == WarningKind {
+		text = warningTemplate
+		n.Subject = warningSubject
+
+		if text == "" {
+			text = DefaultWarningTemplate
+		}
+	}
+
+	parsedTemplate, err := NewTemplate(text)
+
+	if err != nil {
+		return err
+	}
+
+	var rendered bytes.Buffer
+
+	if err := parsedTemplate.Execute(&rendered, n); err != nil {
+		return err
+	}
+
+	n.Text = rendered.String()
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Case: format durations for humans.
+// Where: durations are less than half a minute, minutes, hours with minutes and days with hours.
+// Expected: days and hours are shown without minutes, shorter durations are shown with minutes.
+func TestDurationString(t *testing.T) {
+	// Set up data for tests.
+	cases := map[time.Duration]string{
+		29 * time.Second:                  "0m",
+		45 * time.Minute:                  "45m",
+		2*time.Hour + 30*time.Minute:      "2h30m",
+		76*time.Hour + 15*time.Minute:     "3d4h",
+		48*time.Hour + 10*time.Minute:     "2d",
+		time.Hour + 59*time.Second:        "1h1m",
+		5*time.Hour + 30*time.Millisecond: "5h",
+	}
+
+	// Testing.
+	for duration, expected := range cases {
+		assert.Equal(t, expected, Duration(duration).String(), "Duration is formatted for humans.")
+	}
+}
+
+// Case: render notifications.
+// Where: warning and deleted notifications are rendered by default templates, deleted one also by a custom template.
+// Expected: texts contain namespaces' names, ages and pull requests' numbers, subjects depend on kinds.
+func TestNotificationRender(t *testing.T) {
+	// Set up data for tests.
+	warning := Notification{
+		Kind:               WarningKind,
+		StaleFeatureBranch: "stale feature branch team/back-end",
+		Namespaces: []Namespace{
+			{
+				Name:              "back-end-pr-7",
+				Age:               Duration(76 * time.Hour),
+				PullRequestNumber: 7,
+				DeleteIn:          Duration(2 * time.Hour),
+			},
+			{
+				Name:     "back-end-staging",
+				Age:      Duration(240 * time.Hour),
+				DeleteIn: Duration(90 * time.Minute),
+			},
+		},
+	}
+
+	deleted := Notification{
+		Kind:               DeletedKind,
+		StaleFeatureBranch: "stale feature branch team/back-end",
+		Namespaces: []Namespace{
+			{Name: "back-end-pr-7", Age: Duration(76 * time.Hour), PullRequestNumber: 7},
+			{Name: "back-end-staging", Age: Duration(240 * time.Hour)},
+		},
+	}
+
+	// Testing.
+	if err := warning.Render("", ""); err != nil {
+		t.Fatalf("An error occurred while rendering a notification: (%v)", err)
+	}
+
+	assert.Equal(t, warningSubject, warning.Subject, "Warning has the warning's subject.")
+	assert.Equal(
+		t,
+		"Namespace back-end-pr-7 of pull request #7 is 3d4h old and will be deleted in 2h by stale feature branch "+
+			"team/back-end.\n"+
+			"Namespace back-end-staging is 10d old and will be deleted in 1h30m by stale feature branch team/back-end.",
+		warning.Text,
+		"Warning is rendered by the default template.",
+	)
+
+	if err := deleted.Render("", ""); err != nil {
+		t.Fatalf("An error occurred while rendering a notification: (%v)", err)
+	}
+
+	assert.Equal(t, deletedSubject, deleted.Subject, "Summary has the deleted's subject.")
+	assert.Equal(
+		t,
+		"2 namespaces have been deleted by stale feature branch team/back-end:\n"+
+			"- back-end-pr-7 of pull request #7, 3d4h old\n"+
+			"- back-end-staging, 10d old",
+		deleted.Text,
+		"Summary is rendered by the default template.",
+	)
+
+	if err := deleted.Render("", "Deleted: {{ range .Namespaces }}{{ .Name }} {{ end }}"); err != nil {
+		t.Fatalf("An error occurred while rendering a notification: (%v)", err)
+	}
+
+	assert.Equal(t, "Deleted: back-end-pr-7 back-end-staging ", deleted.Text, "Summary is rendered by the custom template.")
+}
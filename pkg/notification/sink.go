@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+)
+
+// Sink sends rendered notifications.
+type Sink interface {
+	Send(notification Notification) error
+}
+
+// SlackSink sends notifications' texts to a Slack's incoming webhook.
+type SlackSink struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+var _ Sink = &SlackSink{}
+
+func (s *SlackSink) Send(notification Notification) error {
+	return postJSON(s.HTTPClient, s.WebhookURL, map[string]string{"text": notification.Text})
+}
+
+// TeamsSink sends notifications' texts to a Microsoft Teams' incoming webhook.
+type TeamsSink struct {
+	WebhookURL string
+	HTTPClient *http.Client
+}
+
+var _ Sink = &TeamsSink{}
+
+func (s *TeamsSink) Send(notification Notification) error {
+	return postJSON(s.HTTPClient, s.WebhookURL, map[string]string{
+		"title": notification.Subject,
+		"text":  notification.Text,
+	})
+}
+
+// WebhookSink sends whole notifications as JSON to a generic webhook.
+type WebhookSink struct {
+	URL        string
+	HTTPClient *http.Client
+}
+
+var _ Sink = &WebhookSink{}
+
+func (s *WebhookSink) Send(notification Notification) error {
+	return postJSON(s.HTTPClient, s.URL, notification)
+}
+
+// SMTPSink sends notifications as plain text emails. Credentials are sent by PLAIN authentication if the username is
+// set, the server should support TLS then.
+type SMTPSink struct {
+	Address  string
+	Username string
+	Password string
+	From     string
+	To       []string
+
+	sendMail func(address string, auth smtp.Auth, from string, to []string, message []byte) error
+}
+
+var _ Sink = &SMTPSink{}
+
+func (s *SMTPSink) Send(notification Notification) error {
+	var auth smtp.Auth
+
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Address)
+
+		if err != nil {
+			return fmt.Errorf("invalid smtp address %q: %v", s.Address, err)
+		}
+
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+
+	message := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From,
+		strings.Join(s.To, ", "),
+		notification.Subject,
+		strings.ReplaceAll(notification.Text, "\n", "\r\n"),
+	)
+
+	sendMail := s.sendMail
+
+	if sendMail == nil {
+		sendMail = smtp.SendMail
+	}
+
+	return sendMail(s.Address, auth, s.From, s.To, []byte(message))
+}
+
+func postJSON(httpClient *http.Client, webhookURL string, body interface{}) error {
+	payload, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	response, err := httpClient.Post(webhookURL, "application/json", bytes.NewReader(payload))
+
+	if urlErr, isURLErr := err.(*url.Error); isURLErr {
+		// Webhooks' URLs are secrets, so they aren't reported.
+		return fmt.Errorf("unable to post notification: %v", urlErr.Err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		responseBody, _ := ioutil.ReadAll(response.Body)
+		return fmt.Errorf("unexpected status of notification webhook: %s: %s", response.Status, responseBody)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package notification
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Case: send notifications to webhooks.
+// Where: local stand-in receives notifications of Slack, Microsoft Teams and generic webhook sinks.
+// Expected: Slack and Microsoft Teams receive texts, generic webhook receives the whole notification.
+func TestWebhookSinks(t *testing.T) {
+	// Set up data for tests.
+	var bodies []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var body map[string]interface{}
+
+		payload, _ := ioutil.ReadAll(request.Body)
+
+		if err := json.Unmarshal(payload, &body); err != nil {
+			t.Errorf("An error occurred while decoding a notification: (%v)", err)
+		}
+
+		bodies = append(bodies, body)
+	}))
+
+	defer server.Close()
+
+	notification := Notification{
+		Kind:               DeletedKind,
+		StaleFeatureBranch: "stale feature branch team/back-end",
+		Namespaces:         []Namespace{{Name: "back-end-pr-7", PullRequestNumber: 7}},
+		Subject:            deletedSubject,
+		Text:               "Namespace back-end-pr-7 has been deleted.",
+	}
+
+	sinks := []Sink{
+		&SlackSink{WebhookURL: server.URL},
+		&TeamsSink{WebhookURL: server.URL},
+		&WebhookSink{URL: server.URL},
+	}
+
+	// Testing.
+	for _, sink := range sinks {
+		if err := sink.Send(notification); err != nil {
+			t.Fatalf("An error occurred while sending a notification: (%v)", err)
+		}
+	}
+
+	if !assert.Len(t, bodies, 3, "Every sink sends the notification.") {
+		return
+	}
+
+	assert.Equal(t, map[string]interface{}{"text": notification.Text}, bodies[0], "Slack receives the text.")
+	assert.Equal(t, notification.Text, bodies[1]["text"], "Microsoft Teams receives the text.")
+	assert.Equal(t, string(DeletedKind), bodies[2]["kind"], "Generic webhook receives the notification's kind.")
+	assert.Equal(
+		t,
+		[]interface{}{map[string]interface{}{"name": "back-end-pr-7", "age": "0m", "pullRequestNumber": float64(7)}},
+		bodies[2]["namespaces"],
+		"Generic webhook receives the notification's namespaces.",
+	)
+}
+
+// Case: send a notification by email.
+// Where: SMTP sink with credentials and two recipients.
+// Expected: email is sent to the recipients with the subject and the text, credentials are used.
+func TestSMTPSink(t *testing.T) {
+	// Set up data for tests.
+	var (
+		sentAddress    string
+		sentAuth       smtp.Auth
+		sentRecipients []string
+		sentMessage    string
+	)
+
+	sink := SMTPSink{
+		Address:  "smtp.example.com:587",
+		Username: "operator",
+		Password: "secret",
+		From:     "operator@example.com",
+		To:       []string{"back-end@example.com", "front-end@example.com"},
+		sendMail: func(address string, auth smtp.Auth, from string, to []string, message []byte) error {
+			sentAddress, sentAuth, sentRecipients, sentMessage = address, auth, to, string(message)
+			return nil
+		},
+	}
+
+	// Testing.
+	err := sink.Send(Notification{Subject: deletedSubject, Text: "Namespaces have been deleted:\n- back-end-pr-7"})
+
+	if err != nil {
+		t.Fatalf("An error occurred while sending a notification: (%v)", err)
+	}
+
+	assert.Equal(t, "smtp.example.com:587", sentAddress, "Email is sent to the server.")
+	assert.NotNil(t, sentAuth, "Credentials are used.")
+	assert.Equal(t, sink.To, sentRecipients, "Email is sent to the recipients.")
+	assert.Equal(
+		t,
+		"From: operator@example.com\r\n"+
+			"To: back-end@example.com, front-end@example.com\r\n"+
+			"Subject: Feature branches' namespaces have been deleted\r\n"+
+			"Content-Type: text/plain; charset=UTF-8\r\n"+
+			"\r\n"+
+			"Namespaces have been deleted:\r\n- back-end-pr-7\r\n",
+		sentMessage,
+		"Email contains the subject and the text.",
+	)
+}
@@ -0,0 +1,28 @@
+package receiver
+
+const (
+	// DefaultBindAddress is used if PULL_REQUEST_EVENTS_BIND_ADDRESS environment variable isn't set
+	DefaultBindAddress = ":8082"
+	// GitHubPath receives GitHub's pull request events
+	GitHubPath = "/github"
+	// GitLabPath receives GitLab's merge request events
+	GitLabPath = "/gitlab"
+)
+
+const (
+	gitHubEventHeader     = "X-GitHub-Event"
+	gitHubSignatureHeader = "X-Hub-Signature-256"
+	gitHubSignaturePrefix = "sha256="
+	gitHubPullRequestKind = "pull_request"
+	gitHubClosedAction    = "closed"
+
+	gitLabTokenHeader      = "X-Gitlab-Token"
+	gitLabMergeRequestKind = "merge_request"
+	gitLabCloseAction      = "close"
+	gitLabMergeAction      = "merge"
+)
+
+const (
+	// maxPayloadSize is the maximum size of GitHub's payloads
+	maxPayloadSize = 25 << 20
+)
@@ -0,0 +1,120 @@
+package receiver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+)
+
+// errUnauthorized means the event isn't sent by the Git hosting provider the receiver shares the secret with
+var errUnauthorized = errors.New("signature or token is invalid")
+
+// PullRequestEvent is a pull request closed or merged on a Git hosting provider.
+type PullRequestEvent struct {
+	Provider   featurebranchv2.GitProviderType
+	Repository string
+	Number     int
+}
+
+// gitHubPullRequestPayload is a part of GitHub's pull request event
+type gitHubPullRequestPayload struct {
+	Action     string `json:"action"`
+	Number     int    `json:"number"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// gitLabMergeRequestPayload is a part of GitLab's merge request event
+type gitLabMergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID    int    `json:"iid"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// ParseGitHubEvent verifies the HMAC signature of GitHub's event by the secret and parses it. Returns false if the
+// event isn't a closed pull request, e.g. a ping or an opened pull request.
+func ParseGitHubEvent(header http.Header, body []byte, secret string) (PullRequestEvent, bool, error) {
+	if !IsGitHubSignatureValid(header.Get(gitHubSignatureHeader), body, secret) {
+		return PullRequestEvent{}, false, errUnauthorized
+	}
+
+	if header.Get(gitHubEventHeader) != gitHubPullRequestKind {
+		return PullRequestEvent{}, false, nil
+	}
+
+	var payload gitHubPullRequestPayload
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return PullRequestEvent{}, false, fmt.Errorf("invalid pull request event: %v", err)
+	}
+
+	if payload.Action != gitHubClosedAction {
+		return PullRequestEvent{}, false, nil
+	}
+
+	return PullRequestEvent{
+		Provider:   featurebranchv2.GitHubProviderType,
+		Repository: payload.Repository.FullName,
+		Number:     payload.Number,
+	}, true, nil
+}
+
+// ParseGitLabEvent verifies the secret token of GitLab's event and parses it. GitLab doesn't sign events, the token is
+// compared in constant time instead. Returns false if the event isn't a closed or merged merge request.
+func ParseGitLabEvent(header http.Header, body []byte, token string) (PullRequestEvent, bool, error) {
+	if subtle.ConstantTimeCompare([]byte(header.Get(gitLabTokenHeader)), []byte(token)) != 1 {
+		return PullRequestEvent{}, false, errUnauthorized
+	}
+
+	var payload gitLabMergeRequestPayload
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return PullRequestEvent{}, false, fmt.Errorf("invalid merge request event: %v", err)
+	}
+
+	if payload.ObjectKind != gitLabMergeRequestKind {
+		return PullRequestEvent{}, false, nil
+	}
+
+	if payload.ObjectAttributes.Action != gitLabCloseAction && payload.ObjectAttributes.Action != gitLabMergeAction {
+		return PullRequestEvent{}, false, nil
+	}
+
+	return PullRequestEvent{
+		Provider:   featurebranchv2.GitLabProviderType,
+		Repository: payload.Project.PathWithNamespace,
+		Number:     payload.ObjectAttributes.IID,
+	}, true, nil
+}
+
+// IsGitHubSignatureValid reports whether the signature is HMAC SHA-256 of the body by the secret.
+func IsGitHubSignatureValid(signature string, body []byte, secret string) bool {
+	if !strings.HasPrefix(signature, gitHubSignaturePrefix) {
+		return false
+	}
+
+	receivedMAC, err := hex.DecodeString(strings.TrimPrefix(signature, gitHubSignaturePrefix))
+
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(receivedMAC, mac.Sum(nil))
+}
@@ -0,0 +1,226 @@
+package receiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers/stalefeaturebranch"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+var logger = logf.Log.WithName("pull-request-events-receiver")
+
+// Receiver receives events of pull requests closed on Git hosting providers and triggers immediate runs of stale
+// feature branches matching the pull requests' namespaces, so they don't wait for their schedules.
+type Receiver struct {
+	Client       client.Client
+	Triggers     *stalefeaturebranch.RunTriggers
+	States       *stalefeaturebranch.PullRequestStateCache
+	BindAddress  string
+	GitHubSecret string
+	GitLabToken  string
+}
+
+var _ manager.Runnable = &Receiver{}
+var _ http.Handler = &Receiver{}
+
+// RegisterReceiver adds the receiver to the manager if GITHUB_WEBHOOK_SECRET or GITLAB_WEBHOOK_TOKEN environment
+// variables are set. Events of a provider are rejected if its secret isn't set.
+func RegisterReceiver(mgr manager.Manager, triggers *stalefeaturebranch.RunTriggers, states *stalefeaturebranch.PullRequestStateCache) error {
+	receiver := &Receiver{
+		Client:       mgr.GetClient(),
+		Triggers:     triggers,
+		States:       states,
+		BindAddress:  os.Getenv("PULL_REQUEST_EVENTS_BIND_ADDRESS"),
+		GitHubSecret: os.Getenv("GITHUB_WEBHOOK_SECRET"),
+		GitLabToken:  os.Getenv("GITLAB_WEBHOOK_TOKEN"),
+	}
+
+	if receiver.GitHubSecret == "" && receiver.GitLabToken == "" {
+		return nil
+	}
+
+	if receiver.BindAddress == "" {
+		receiver.BindAddress = DefaultBindAddress
+	}
+
+	return mgr.Add(receiver)
+}
+
+// Start serves events until the stop channel is closed.
+func (r *Receiver) Start(stop <-chan struct{}) error {
+	server := &http.Server{Addr: r.BindAddress, Handler: r}
+	errs := make(chan error, 1)
+
+	go func() {
+		logger.Info("Pull request events receiver is starting.", "bindAddress", r.BindAddress)
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-stop:
+		return server.Shutdown(context.Background())
+	case err := <-errs:
+		return err
+	}
+}
+
+func (r *Receiver) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		http.Error(writer, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(writer, request.Body, maxPayloadSize))
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	var (
+		pullRequestEvent PullRequestEvent
+		isClosed         bool
+	)
+
+	switch {
+	case request.URL.Path == GitHubPath && r.GitHubSecret != "":
+		pullRequestEvent, isClosed, err = ParseGitHubEvent(request.Header, body, r.GitHubSecret)
+	case request.URL.Path == GitLabPath && r.GitLabToken != "":
+		pullRequestEvent, isClosed, err = ParseGitLabEvent(request.Header, body, r.GitLabToken)
+	default:
+		http.NotFound(writer, request)
+		return
+	}
+
+	if err == errUnauthorized {
+		http.Error(writer, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !isClosed {
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if _, err := r.Dispatch(pullRequestEvent); err != nil {
+		logger.Error(err, "Unable to dispatch a pull request event.", "repository", pullRequestEvent.Repository)
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.WriteHeader(http.StatusAccepted)
+}
+
+// Dispatch triggers runs of stale feature branches and cluster stale feature branches with the pull request's
+// repository that match a namespace of the pull request. Cached state of the pull request is forgotten, so the runs
+// see it closed. Returns names of triggered stale feature branches.
+func (r *Receiver) Dispatch(pullRequestEvent PullRequestEvent) ([]string, error) {
+	var staleFeatureBranches featurebranchv2.StaleFeatureBranchList
+
+	if err := r.Client.List(context.TODO(), &staleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	var clusterStaleFeatureBranches featurebranchv2.ClusterStaleFeatureBranchList
+
+	if err := r.Client.List(context.TODO(), &clusterStaleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	candidates := staleFeatureBranches.Items
+
+	for _, clusterStaleFeatureBranch := range clusterStaleFeatureBranches.Items {
+		candidates = append(candidates, stalefeaturebranch.NewClusterScopedStaleFeatureBranch(clusterStaleFeatureBranch))
+	}
+
+	r.States.Forget(pullRequestEvent.Provider, pullRequestEvent.Repository, pullRequestEvent.Number)
+
+	var triggered []string
+
+	for _, staleFeatureBranch := range candidates {
+		isMatched, err := r.isPullRequestMatched(staleFeatureBranch, pullRequestEvent)
+
+		if err != nil {
+			return triggered, err
+		}
+
+		if !isMatched {
+			continue
+		}
+
+		description := stalefeaturebranch.DescribeStaleFeatureBranch(staleFeatureBranch)
+
+		if !r.Triggers.Trigger(staleFeatureBranch) {
+			logger.Info("Run isn't triggered as the queue is full.", "staleFeatureBranch", description)
+			continue
+		}
+
+		logger.Info(
+			"Run is triggered by a closed pull request.",
+			"staleFeatureBranch", description,
+			"repository", pullRequestEvent.Repository,
+			"number", pullRequestEvent.Number,
+		)
+
+		triggered = append(triggered, description)
+	}
+
+	return triggered, nil
+}
+
+// isPullRequestMatched reports whether the stale feature branch specifies the pull request's repository and matches a
+// namespace of the pull request by its number pattern. Stale feature branches with invalid specifications are ignored.
+func (r *Receiver) isPullRequestMatched(staleFeatureBranch featurebranchv2.StaleFeatureBranch, pullRequestEvent PullRequestEvent) (bool, error) {
+	pullRequestSpec := staleFeatureBranch.Spec.PullRequest
+
+	if pullRequestSpec == nil || pullRequestSpec.Provider != pullRequestEvent.Provider {
+		return false, nil
+	}
+
+	if !strings.EqualFold(pullRequestSpec.Repository, pullRequestEvent.Repository) {
+		return false, nil
+	}
+
+	matcher, err := stalefeaturebranch.NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err == nil && !stalefeaturebranch.IsClusterScoped(staleFeatureBranch) {
+		err = matcher.RestrictToOwner(staleFeatureBranch.Namespace)
+	}
+
+	if err != nil {
+		return false, nil
+	}
+
+	namespaces, err := stalefeaturebranch.ListNamespaces(r.Client, matcher.ListOptions()...)
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, namespace := range namespaces {
+		if _, isNamespaceMatched := matcher.Match(namespace); !isNamespaceMatched {
+			continue
+		}
+
+		number, isNumbered, err := stalefeaturebranch.GetPullRequestNumber(*pullRequestSpec, namespace)
+
+		if err == nil && isNumbered && number == pullRequestEvent.Number {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,283 @@
+package receiver
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers/stalefeaturebranch"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("An error occurred while registering Kubernetes schemes: (%v)", err)
+	}
+
+	if err := featurebranchv2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("An error occurred while registering stale feature branch schemes: (%v)", err)
+	}
+
+	return s
+}
+
+func newStaleFeatureBranchSpec(provider featurebranchv2.GitProviderType, repository string) featurebranchv2.StaleFeatureBranchSpec {
+	return featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "back-end-pr-",
+		StaleAfter:         metav1.Duration{Duration: time.Hour},
+		PullRequest: &featurebranchv2.PullRequestSpec{
+			Provider:      provider,
+			Repository:    repository,
+			NumberPattern: `-pr-(\d+)$`,
+		},
+	}
+}
+
+func newNamespace(name string, owner string) *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{featurebranch.OwnerLabel: owner},
+		},
+	}
+}
+
+func sign(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Case: receive GitHub's pull request events.
+// Where: stale feature branches of the pull request's repository and of another one own the pull request's namespace,
+// events are a closed pull request, an opened pull request and a closed one with an invalid signature.
+// Expected: only the run of the stale feature branch of the pull request's repository is triggered by the closed pull
+// request, the opened one is ignored, the invalid signature is rejected.
+func TestReceiverGitHub(t *testing.T) {
+	// Set up data for tests.
+	var (
+		secret   = "github-secret"
+		triggers = stalefeaturebranch.NewRunTriggers()
+	)
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "back-end", Namespace: "team"},
+		Spec:       newStaleFeatureBranchSpec(featurebranchv2.GitHubProviderType, "owner/back-end"),
+	}
+
+	anotherStaleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "front-end", Namespace: "team"},
+		Spec:       newStaleFeatureBranchSpec(featurebranchv2.GitHubProviderType, "owner/front-end"),
+	}
+
+	receiver := &Receiver{
+		Client: fake.NewFakeClientWithScheme(
+			newScheme(t), staleFeatureBranch, anotherStaleFeatureBranch, newNamespace("back-end-pr-7", "team"),
+		),
+		Triggers:     triggers,
+		GitHubSecret: secret,
+	}
+
+	send := func(action string, signature func(body []byte) string) int {
+		body := []byte(`{"action": "` + action + `", "number": 7, "repository": {"full_name": "owner/back-end"}}`)
+		request := httptest.NewRequest(http.MethodPost, GitHubPath, bytes.NewReader(body))
+		request.Header.Set(gitHubEventHeader, gitHubPullRequestKind)
+		request.Header.Set(gitHubSignatureHeader, signature(body))
+
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, request)
+
+		return recorder.Code
+	}
+
+	validSignature := func(body []byte) string { return sign(body, secret) }
+	invalidSignature := func(body []byte) string { return sign(body, "another-secret") }
+
+	// Testing.
+	assert.Equal(t, http.StatusUnauthorized, send("closed", invalidSignature), "Invalid signature is rejected.")
+	assert.Equal(t, http.StatusNoContent, send("opened", validSignature), "Opened pull request is ignored.")
+	assert.Empty(t, triggers.StaleFeatureBranchEvents, "Runs aren't triggered by rejected and ignored events.")
+
+	assert.Equal(t, http.StatusAccepted, send("closed", validSignature), "Closed pull request is accepted.")
+
+	if assert.Len(t, triggers.StaleFeatureBranchEvents, 1, "The only run is triggered.") {
+		triggeredEvent := <-triggers.StaleFeatureBranchEvents
+		assert.Equal(t, "back-end", triggeredEvent.Meta.GetName(), "Run of the repository's stale feature branch is triggered.")
+	}
+
+	assert.True(t, triggers.Take(*staleFeatureBranch), "Run of the repository's stale feature branch is requested.")
+	assert.False(t, triggers.Take(*anotherStaleFeatureBranch), "Run of another repository's stale feature branch isn't requested.")
+}
+
+// Case: receive GitLab's merge request events.
+// Where: cluster stale feature branch of the merge request's project matches the merge request's namespace.
+// Expected: merged merge request with the valid token triggers the cluster stale feature branch's run, the invalid token
+// is rejected.
+func TestReceiverGitLab(t *testing.T) {
+	// Set up data for tests.
+	var (
+		token    = "gitlab-token"
+		triggers = stalefeaturebranch.NewRunTriggers()
+	)
+
+	clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "back-end"},
+		Spec:       newStaleFeatureBranchSpec(featurebranchv2.GitLabProviderType, "group/back-end"),
+	}
+
+	receiver := &Receiver{
+		Client: fake.NewFakeClientWithScheme(
+			newScheme(t), clusterStaleFeatureBranch, newNamespace("back-end-pr-7", "team"),
+		),
+		Triggers:    triggers,
+		GitLabToken: token,
+	}
+
+	send := func(token string) int {
+		body := []byte(`{
+			"object_kind": "merge_request",
+			"project": {"path_with_namespace": "group/back-end"},
+			"object_attributes": {"iid": 7, "action": "merge"}
+		}`)
+
+		request := httptest.NewRequest(http.MethodPost, GitLabPath, bytes.NewReader(body))
+		request.Header.Set(gitLabTokenHeader, token)
+
+		recorder := httptest.NewRecorder()
+		receiver.ServeHTTP(recorder, request)
+
+		return recorder.Code
+	}
+
+	// Testing.
+	assert.Equal(t, http.StatusUnauthorized, send("another-token"), "Invalid token is rejected.")
+	assert.Equal(t, http.StatusAccepted, send(token), "Merged merge request is accepted.")
+
+	if assert.Len(t, triggers.ClusterStaleFeatureBranchEvents, 1, "The only run is triggered.") {
+		triggeredEvent := <-triggers.ClusterStaleFeatureBranchEvents
+
+		_, isClusterScoped := triggeredEvent.Object.(*featurebranchv2.ClusterStaleFeatureBranch)
+		assert.True(t, isClusterScoped, "Run of the cluster stale feature branch is triggered.")
+	}
+}
+
+// Case: clean up the namespace of a closed pull request end to end.
+// Where: namespace is created right now, so it isn't stale, its pull request is open on the first run and closed before
+// GitHub's event is received, the stale feature branch isn't due by its interval.
+// Expected: the event triggers a run that deletes the namespace right away.
+func TestReceiverDeletesNamespaceOfClosedPullRequest(t *testing.T) {
+	// Set up data for tests.
+	var (
+		secret            = "github-secret"
+		pullRequestState  = `{"state": "open", "merged": false}`
+		triggers          = stalefeaturebranch.NewRunTriggers()
+		pullRequestStates = stalefeaturebranch.NewPullRequestStateCache()
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/repos/owner/back-end/pulls/7" {
+			http.NotFound(writer, request)
+			return
+		}
+
+		fmt.Fprint(writer, pullRequestState)
+	}))
+
+	defer server.Close()
+
+	spec := newStaleFeatureBranchSpec(featurebranchv2.GitHubProviderType, "owner/back-end")
+	spec.Interval = metav1.Duration{Duration: time.Hour}
+	spec.PullRequest.URL = server.URL
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		ObjectMeta: metav1.ObjectMeta{Name: "back-end", Namespace: "team"},
+		Spec:       spec,
+	}
+
+	namespace := newNamespace("back-end-pr-7", "team")
+	namespace.CreationTimestamp = metav1.Now()
+
+	c := fake.NewFakeClientWithScheme(newScheme(t), staleFeatureBranch, namespace)
+
+	reconciler := &stalefeaturebranch.ReconcileStaleFeatureBranch{
+		Client:            c,
+		Scheme:            newScheme(t),
+		Recorder:          record.NewFakeRecorder(100),
+		Triggers:          triggers,
+		PullRequestStates: pullRequestStates,
+	}
+
+	receiver := &Receiver{
+		Client:       c,
+		Triggers:     triggers,
+		States:       pullRequestStates,
+		GitHubSecret: secret,
+	}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team", Name: "back-end"}}
+
+	if _, err := reconciler.Reconcile(request); err != nil {
+		t.Fatalf("An error occurred while running the stale feature branch: (%v)", err)
+	}
+
+	assert.NoError(
+		t,
+		c.Get(context.TODO(), types.NamespacedName{Name: "back-end-pr-7"}, &corev1.Namespace{}),
+		"Namespace of the open pull request isn't deleted.",
+	)
+
+	pullRequestState = `{"state": "closed", "merged": true}`
+
+	body := []byte(`{"action": "closed", "number": 7, "repository": {"full_name": "owner/back-end"}}`)
+	eventRequest := httptest.NewRequest(http.MethodPost, GitHubPath, bytes.NewReader(body))
+	eventRequest.Header.Set(gitHubEventHeader, gitHubPullRequestKind)
+	eventRequest.Header.Set(gitHubSignatureHeader, sign(body, secret))
+
+	recorder := httptest.NewRecorder()
+
+	// Testing.
+	receiver.ServeHTTP(recorder, eventRequest)
+
+	assert.Equal(t, http.StatusAccepted, recorder.Code, "Closed pull request is accepted.")
+
+	if !assert.Len(t, triggers.StaleFeatureBranchEvents, 1, "Run is triggered.") {
+		return
+	}
+
+	triggeredEvent := <-triggers.StaleFeatureBranchEvents
+	triggeredRequest := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: triggeredEvent.Meta.GetNamespace(),
+			Name:      triggeredEvent.Meta.GetName(),
+		},
+	}
+
+	if _, err := reconciler.Reconcile(triggeredRequest); err != nil {
+		t.Fatalf("An error occurred while running the triggered stale feature branch: (%v)", err)
+	}
+
+	err := c.Get(context.TODO(), types.NamespacedName{Name: "back-end-pr-7"}, &corev1.Namespace{})
+	assert.True(t, errors.IsNotFound(err), "Namespace of the closed pull request is deleted right away.")
+}
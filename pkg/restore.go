@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/backup"
+
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// RestoreOptions defines where the restore command loads a backup bundle from.
+type RestoreOptions struct {
+	BundlePath         string
+	Sink               string
+	Name               string
+	ConfigMapNamespace string
+	Directory          string
+	S3                 featurebranchv2.S3BackupSpec
+}
+
+// Restore re-applies the backup bundle of a deleted namespace into a fresh namespace. Bundle is read from a file, from
+// the standard input if its path is "-", or loaded by its name from the sink it's stored to by the operator.
+func Restore(arguments []string) error {
+	flags := flag.NewFlagSet(RestoreCommand, flag.ContinueOnError)
+
+	var options RestoreOptions
+
+	flags.StringVar(&options.BundlePath, "bundle", "", "Path to the backup bundle, \"-\" to read it from the standard input.")
+	flags.StringVar(&options.Sink, "sink", "", "Sink to load the backup bundle from by its name: ConfigMap, Directory or S3.")
+	flags.StringVar(&options.Name, "name", "", "Name of the backup bundle in the sink.")
+	flags.StringVar(
+		&options.ConfigMapNamespace,
+		"configmap-namespace",
+		os.Getenv("OPERATOR_NAMESPACE"),
+		"Namespace of ConfigMap sink's config maps, the operator's namespace by default.",
+	)
+	flags.StringVar(&options.Directory, "directory", "", "Directory of Directory sink's bundles.")
+	flags.StringVar(&options.S3.Endpoint, "s3-endpoint", "", "Endpoint of S3 sink.")
+	flags.StringVar(&options.S3.Bucket, "s3-bucket", "", "Bucket of S3 sink.")
+	flags.StringVar(&options.S3.Prefix, "s3-prefix", "", "Prefix of S3 sink's keys.")
+	flags.StringVar(&options.S3.Region, "s3-region", "", "Region of S3 sink.")
+
+	namespace := flags.String("namespace", "", "Namespace to restore the backup bundle into.")
+
+	if err := flags.Parse(arguments); err != nil {
+		return err
+	}
+
+	if *namespace == "" {
+		return errors.New("namespace is required")
+	}
+
+	cfg, err := config.GetConfig()
+
+	if err != nil {
+		return err
+	}
+
+	bundle, err := LoadBundle(cfg, options)
+
+	if err != nil {
+		return err
+	}
+
+	bundler, err := backup.NewBundler(cfg)
+
+	if err != nil {
+		return err
+	}
+
+	return bundler.Restore(bundle, *namespace)
+}
+
+// LoadBundle loads the backup bundle from the file or from the sink by its name. Credentials of S3 sink are read from
+// S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY environment variables.
+func LoadBundle(cfg *rest.Config, options RestoreOptions) ([]byte, error) {
+	if options.BundlePath != "" {
+		if options.Sink != "" {
+			return nil, errors.New("either bundle or sink is required, not both")
+		}
+
+		if options.BundlePath == ReadFromStandardInput {
+			return ioutil.ReadAll(os.Stdin)
+		}
+
+		return ioutil.ReadFile(options.BundlePath)
+	}
+
+	if options.Sink == "" || options.Name == "" {
+		return nil, errors.New("either bundle or both sink and name are required")
+	}
+
+	var sink backup.Sink
+
+	switch featurebranchv2.BackupSinkType(options.Sink) {
+	case featurebranchv2.ConfigMapBackupSinkType:
+		if options.ConfigMapNamespace == "" {
+			return nil, errors.New("config map namespace is required by ConfigMap sink")
+		}
+
+		c, err := client.New(cfg, client.Options{})
+
+		if err != nil {
+			return nil, err
+		}
+
+		sink = &backup.ConfigMapSink{Client: c, Namespace: options.ConfigMapNamespace}
+	case featurebranchv2.DirectoryBackupSinkType:
+		if options.Directory == "" {
+			return nil, errors.New("directory is required by Directory sink")
+		}
+
+		sink = &backup.DirectorySink{Path: options.Directory}
+	case featurebranchv2.S3BackupSinkType:
+		if options.S3.Endpoint == "" || options.S3.Bucket == "" {
+			return nil, errors.New("endpoint and bucket are required by S3 sink")
+		}
+
+		sink = &backup.S3Sink{
+			Endpoint:        options.S3.Endpoint,
+			Bucket:          options.S3.Bucket,
+			Prefix:          options.S3.Prefix,
+			Region:          options.S3.Region,
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			HTTPClient:      &http.Client{Timeout: S3TimeoutSeconds * time.Second},
+		}
+	default:
+		return nil, fmt.Errorf("unknown backup sink %q", options.Sink)
+	}
+
+	return sink.Load(options.Name)
+}
@@ -0,0 +1,19 @@
+package webhooks
+
+const (
+	// DefaulterPath is the path the defaulting webhook of stale feature branches is served on
+	DefaulterPath = "/mutate-stale-feature-branch"
+	// ValidatorPath is the path the validating webhook of stale feature branches is served on
+	ValidatorPath = "/validate-stale-feature-branch"
+	// ConverterPath is the path the conversion webhook between versions of stale feature branches is served on
+	ConverterPath = "/convert"
+)
+
+const (
+	// MinNamespaceSubstringLength is the shortest namespace substring that isn't likely to match most of the cluster
+	MinNamespaceSubstringLength = 3
+)
+
+const (
+	clusterStaleFeatureBranchKind = "ClusterStaleFeatureBranch"
+)
@@ -0,0 +1,47 @@
+package webhooks
+
+import (
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers/stalefeaturebranch"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// decodeObject decodes either the stale feature branch or the cluster stale feature branch of the request and returns
+// the object and its specifications.
+func decodeObject(decoder *admission.Decoder, request admission.Request) (runtime.Object, *featurebranchv2.StaleFeatureBranchSpec, error) {
+	if request.Kind.Kind == clusterStaleFeatureBranchKind {
+		var clusterStaleFeatureBranch featurebranchv2.ClusterStaleFeatureBranch
+
+		if err := decoder.Decode(request, &clusterStaleFeatureBranch); err != nil {
+			return nil, nil, err
+		}
+
+		return &clusterStaleFeatureBranch, &clusterStaleFeatureBranch.Spec, nil
+	}
+
+	var staleFeatureBranch featurebranchv2.StaleFeatureBranch
+
+	if err := decoder.Decode(request, &staleFeatureBranch); err != nil {
+		return nil, nil, err
+	}
+
+	return &staleFeatureBranch, &staleFeatureBranch.Spec, nil
+}
+
+// decodeStaleFeatureBranch decodes the request's object as a stale feature branch, cluster stale feature branches are
+// decoded as stale feature branches without a namespace.
+func decodeStaleFeatureBranch(decoder *admission.Decoder, request admission.Request) (featurebranchv2.StaleFeatureBranch, error) {
+	object, _, err := decodeObject(decoder, request)
+
+	if err != nil {
+		return featurebranchv2.StaleFeatureBranch{}, err
+	}
+
+	if clusterStaleFeatureBranch, isClusterScoped := object.(*featurebranchv2.ClusterStaleFeatureBranch); isClusterScoped {
+		return stalefeaturebranch.NewClusterScopedStaleFeatureBranch(*clusterStaleFeatureBranch), nil
+	}
+
+	return *object.(*featurebranchv2.StaleFeatureBranch), nil
+}
@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ admission.Handler = &StaleFeatureBranchDefaulter{}
+
+// StaleFeatureBranchDefaulter fills defaults of stale feature branches' and cluster stale feature branches'
+// specifications.
+type StaleFeatureBranchDefaulter struct {
+	Decoder *admission.Decoder
+}
+
+func (d *StaleFeatureBranchDefaulter) Handle(ctx context.Context, request admission.Request) admission.Response {
+	object, spec, err := decodeObject(d.Decoder, request)
+
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	featurebranchv2.SetDefaults(spec)
+
+	defaultedObject, err := json.Marshal(object)
+
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	return admission.PatchResponseFromRaw(request.Object.Raw, defaultedObject)
+}
@@ -0,0 +1,90 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Case: default a stale feature branch created without optional specifications.
+// Where: pattern type, interval, action, stuck terminating minutes and S3 region aren't specified.
+// Expected: patches fill them with the same defaults the custom resource definition does.
+func TestStaleFeatureBranchDefaulter(t *testing.T) {
+	// Set up data for tests.
+	s := newScheme(t)
+
+	defaulter := &StaleFeatureBranchDefaulter{Decoder: newDecoder(t, s)}
+
+	staleFeatureBranch := &featurebranchv2.StaleFeatureBranch{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: featurebranchv2.SchemeGroupVersion.String(),
+			Kind:       "StaleFeatureBranch",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch", Namespace: "team-a"},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespacePattern: "^project-pr-[0-9]+$",
+			StaleAfter:       metav1.Duration{Duration: 24 * time.Hour},
+			Backup: &featurebranchv2.BackupSpec{
+				Sink: featurebranchv2.S3BackupSinkType,
+				S3:   &featurebranchv2.S3BackupSpec{Bucket: "backups"},
+			},
+		},
+	}
+
+	// Testing.
+	response := defaulter.Handle(context.TODO(), newRequest(t, admissionv1beta1.Create, "StaleFeatureBranch", staleFeatureBranch))
+
+	assert.True(t, response.Allowed, "Defaulting is allowed.")
+
+	patches := make(map[string]interface{})
+
+	for _, patch := range response.Patches {
+		patches[patch.Path] = patch.Value
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"/spec/namespacePatternType":  string(featurebranchv2.RegexpNamespacePatternType),
+		"/spec/interval":              featurebranchv2.DefaultInterval.String(),
+		"/spec/action":                string(featurebranchv2.DeleteActionType),
+		"/spec/stuckTerminatingAfter": featurebranchv2.DefaultStuckTerminatingAfter.String(),
+		"/spec/backup/s3/region":      featurebranchv2.DefaultS3Region,
+	}, patches, "Defaults are patched.")
+}
+
+// Case: default a cluster stale feature branch with all optional specifications.
+// Where: all defaulted specifications are specified.
+// Expected: no patches are made.
+func TestStaleFeatureBranchDefaulterClusterScoped(t *testing.T) {
+	// Set up data for tests.
+	s := newScheme(t)
+
+	defaulter := &StaleFeatureBranchDefaulter{Decoder: newDecoder(t, s)}
+
+	clusterStaleFeatureBranch := &featurebranchv2.ClusterStaleFeatureBranch{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: featurebranchv2.SchemeGroupVersion.String(),
+			Kind:       clusterStaleFeatureBranchKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "stale-feature-branch"},
+		Spec: featurebranchv2.StaleFeatureBranchSpec{
+			NamespaceSubstring:    "-pr-",
+			NamespacePatternType:  featurebranchv2.GlobNamespacePatternType,
+			StaleAfter:            metav1.Duration{Duration: 24 * time.Hour},
+			Interval:              metav1.Duration{Duration: 5 * time.Minute},
+			Action:                featurebranchv2.HibernateActionType,
+			StuckTerminatingAfter: metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+
+	// Testing.
+	response := defaulter.Handle(context.TODO(), newRequest(t, admissionv1beta1.Create, clusterStaleFeatureBranchKind, clusterStaleFeatureBranch))
+
+	assert.True(t, response.Allowed, "Defaulting is allowed.")
+	assert.Empty(t, response.Patches, "Specified specifications aren't patched.")
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// RegisterWebhooks registers the defaulting, validating and conversion webhooks of stale feature branches and cluster
+// stale feature branches on the manager's webhook server.
+func RegisterWebhooks(manager manager.Manager) error {
+	decoder, err := admission.NewDecoder(manager.GetScheme())
+
+	if err != nil {
+		return err
+	}
+
+	converter := &conversion.Webhook{}
+
+	if err := converter.InjectScheme(manager.GetScheme()); err != nil {
+		return err
+	}
+
+	server := manager.GetWebhookServer()
+
+	server.Register(DefaulterPath, &webhook.Admission{
+		Handler: &StaleFeatureBranchDefaulter{Decoder: decoder},
+	})
+
+	server.Register(ValidatorPath, &webhook.Admission{
+		Handler: &StaleFeatureBranchValidator{Client: manager.GetClient(), Decoder: decoder},
+	})
+
+	server.Register(ConverterPath, converter)
+
+	return nil
+}
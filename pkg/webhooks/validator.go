@@ -0,0 +1,247 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/controllers/stalefeaturebranch"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ admission.Handler = &StaleFeatureBranchValidator{}
+
+// StaleFeatureBranchValidator rejects stale feature branches and cluster stale feature branches with invalid or
+// dangerous specifications before they are stored.
+type StaleFeatureBranchValidator struct {
+	Client  client.Client
+	Decoder *admission.Decoder
+}
+
+func (v *StaleFeatureBranchValidator) Handle(ctx context.Context, request admission.Request) admission.Response {
+	if request.Operation != admissionv1beta1.Create && request.Operation != admissionv1beta1.Update {
+		return admission.Allowed("")
+	}
+
+	staleFeatureBranch, err := decodeStaleFeatureBranch(v.Decoder, request)
+
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	featurebranchv2.SetDefaults(&staleFeatureBranch.Spec)
+
+	if err := ValidateSpecifications(staleFeatureBranch.Spec); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if err := stalefeaturebranch.ValidateStalenessScope(staleFeatureBranch); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if err := v.ValidateProtectedNamespaces(ctx, staleFeatureBranch); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if err := v.ValidateOverlapping(ctx, staleFeatureBranch); err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	return admission.Allowed("")
+}
+
+// ValidateSpecifications validates the stale feature branch's specifications the same way the operator does before
+// processing them, and additionally rejects namespace substrings that are short enough to match most of the cluster.
+func ValidateSpecifications(spec featurebranchv2.StaleFeatureBranchSpec) error {
+	if spec.NamespaceSubstring != "" && len(spec.NamespaceSubstring) < MinNamespaceSubstringLength {
+		return fmt.Errorf(
+			"namespace substring should be at least %d characters long, got %q",
+			MinNamespaceSubstringLength,
+			spec.NamespaceSubstring,
+		)
+	}
+
+	if _, err := stalefeaturebranch.NewRunSchedule(spec); err != nil {
+		return err
+	}
+
+	if err := stalefeaturebranch.ValidateAction(spec); err != nil {
+		return err
+	}
+
+	if err := stalefeaturebranch.ValidateBackup(spec); err != nil {
+		return err
+	}
+
+	if _, err := stalefeaturebranch.NewStalenessPolicy(spec); err != nil {
+		return err
+	}
+
+	if err := stalefeaturebranch.ValidatePullRequest(spec); err != nil {
+		return err
+	}
+
+	if err := stalefeaturebranch.ValidateNotifications(spec); err != nil {
+		return err
+	}
+
+	if _, err := stalefeaturebranch.NewNamespaceMatcher(spec); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateProtectedNamespaces rejects the stale feature branch if it matches any of the system namespaces or the
+// operator's own namespace without excluding them.
+func (v *StaleFeatureBranchValidator) ValidateProtectedNamespaces(ctx context.Context, staleFeatureBranch featurebranchv2.StaleFeatureBranch) error {
+	matcher, err := stalefeaturebranch.NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		return err
+	}
+
+	for _, protectedNamespace := range stalefeaturebranch.GetProtectedNamespaces() {
+		namespace, err := v.getNamespace(ctx, protectedNamespace)
+
+		if err != nil {
+			return err
+		}
+
+		if _, isMatched := matcher.Match(namespace); isMatched && !matcher.IsExcluded(namespace) {
+			return fmt.Errorf("specifications match protected namespace %s, exclude it or narrow the specifications", protectedNamespace)
+		}
+	}
+
+	return nil
+}
+
+// ValidateOverlapping rejects the stale feature branch if it has the same namespace criteria as another stale feature
+// branch of the same scope, or if any existing namespace is matched by both of them, so conflicting policies aren't
+// applied to the same namespaces.
+func (v *StaleFeatureBranchValidator) ValidateOverlapping(ctx context.Context, staleFeatureBranch featurebranchv2.StaleFeatureBranch) error {
+	matcher, err := newOwnedNamespaceMatcher(staleFeatureBranch)
+
+	if err != nil {
+		return err
+	}
+
+	existingStaleFeatureBranches, err := v.listStaleFeatureBranches(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	namespaces := &corev1.NamespaceList{}
+
+	if err := v.Client.List(ctx, namespaces, matcher.ListOptions()...); err != nil {
+		return err
+	}
+
+	for _, existingStaleFeatureBranch := range existingStaleFeatureBranches {
+		isSameScope := stalefeaturebranch.IsClusterScoped(existingStaleFeatureBranch) == stalefeaturebranch.IsClusterScoped(staleFeatureBranch)
+
+		if isSameScope && existingStaleFeatureBranch.Namespace == staleFeatureBranch.Namespace && existingStaleFeatureBranch.Name == staleFeatureBranch.Name {
+			continue
+		}
+
+		description := stalefeaturebranch.DescribeStaleFeatureBranch(existingStaleFeatureBranch)
+
+		if isSameScope && existingStaleFeatureBranch.Namespace == staleFeatureBranch.Namespace && isSameCriteria(existingStaleFeatureBranch.Spec, staleFeatureBranch.Spec) {
+			return fmt.Errorf("specifications have the same namespace criteria as %s", description)
+		}
+
+		existingMatcher, err := newOwnedNamespaceMatcher(existingStaleFeatureBranch)
+
+		if err != nil {
+			continue
+		}
+
+		for _, namespace := range namespaces.Items {
+			if !isManaged(matcher, namespace) || !isManaged(existingMatcher, namespace) {
+				continue
+			}
+
+			return fmt.Errorf("specifications overlap with %s on namespace %s", description, namespace.Name)
+		}
+	}
+
+	return nil
+}
+
+// getNamespace fetches the namespace to match its labels and annotations. Namespace that doesn't exist yet is matched
+// by its name only.
+func (v *StaleFeatureBranchValidator) getNamespace(ctx context.Context, name string) (corev1.Namespace, error) {
+	namespace := corev1.Namespace{}
+
+	err := v.Client.Get(ctx, types.NamespacedName{Name: name}, &namespace)
+
+	if errors.IsNotFound(err) {
+		return corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+	}
+
+	return namespace, err
+}
+
+// listStaleFeatureBranches lists both stale feature branches and cluster stale feature branches as stale feature
+// branches.
+func (v *StaleFeatureBranchValidator) listStaleFeatureBranches(ctx context.Context) ([]featurebranchv2.StaleFeatureBranch, error) {
+	staleFeatureBranches := &featurebranchv2.StaleFeatureBranchList{}
+
+	if err := v.Client.List(ctx, staleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	clusterStaleFeatureBranches := &featurebranchv2.ClusterStaleFeatureBranchList{}
+
+	if err := v.Client.List(ctx, clusterStaleFeatureBranches); err != nil {
+		return nil, err
+	}
+
+	result := staleFeatureBranches.Items
+
+	for _, clusterStaleFeatureBranch := range clusterStaleFeatureBranches.Items {
+		result = append(result, stalefeaturebranch.NewClusterScopedStaleFeatureBranch(clusterStaleFeatureBranch))
+	}
+
+	return result, nil
+}
+
+// newOwnedNamespaceMatcher creates the matcher restricted to the namespaces the stale feature branch is able to manage.
+func newOwnedNamespaceMatcher(staleFeatureBranch featurebranchv2.StaleFeatureBranch) (*stalefeaturebranch.NamespaceMatcher, error) {
+	matcher, err := stalefeaturebranch.NewNamespaceMatcher(staleFeatureBranch.Spec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !stalefeaturebranch.IsClusterScoped(staleFeatureBranch) {
+		if err := matcher.RestrictToOwner(staleFeatureBranch.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	return matcher, nil
+}
+
+func isManaged(matcher *stalefeaturebranch.NamespaceMatcher, namespace corev1.Namespace) bool {
+	_, isMatched := matcher.Match(namespace)
+	return isMatched && !matcher.IsExcluded(namespace)
+}
+
+func isSameCriteria(first, second featurebranchv2.StaleFeatureBranchSpec) bool {
+	return first.NamespaceSubstring == second.NamespaceSubstring &&
+		first.NamespacePattern == second.NamespacePattern &&
+		first.NamespacePatternType == second.NamespacePatternType &&
+		reflect.DeepEqual(first.NamespaceSelector, second.NamespaceSelector) &&
+		reflect.DeepEqual(first.NamespaceAnnotations, second.NamespaceAnnotations)
+}
@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch"
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newStaleFeatureBranch(name, namespace string, spec featurebranchv2.StaleFeatureBranchSpec) *featurebranchv2.StaleFeatureBranch {
+	return &featurebranchv2.StaleFeatureBranch{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: featurebranchv2.SchemeGroupVersion.String(),
+			Kind:       "StaleFeatureBranch",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       spec,
+	}
+}
+
+func newValidator(t *testing.T, objects ...runtime.Object) *StaleFeatureBranchValidator {
+	s := newScheme(t)
+
+	return &StaleFeatureBranchValidator{
+		Client:  fake.NewFakeClientWithScheme(s, objects...),
+		Decoder: newDecoder(t, s),
+	}
+}
+
+// Case: validate stale feature branches' specifications.
+// Where: namespace substring is too short, schedule is invalid or specifications are valid.
+// Expected: only valid specifications are accepted.
+func TestValidateSpecifications(t *testing.T) {
+	// Testing.
+	err := ValidateSpecifications(featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "-p",
+		StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+		Interval:           metav1.Duration{Duration: time.Minute},
+	})
+	assert.EqualError(t, err, `namespace substring should be at least 3 characters long, got "-p"`, "Short substring is rejected.")
+
+	err = ValidateSpecifications(featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "-pr-",
+		Schedule:           "every day",
+	})
+	assert.Error(t, err, "Invalid schedule is rejected.")
+
+	err = ValidateSpecifications(featurebranchv2.StaleFeatureBranchSpec{
+		StaleAfter: metav1.Duration{Duration: 24 * time.Hour},
+		Interval:   metav1.Duration{Duration: time.Minute},
+	})
+	assert.Error(t, err, "Specifications without namespace criteria are rejected.")
+
+	err = ValidateSpecifications(featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "-pr-",
+		StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+		Schedule:           "0 3 * * *",
+	})
+	assert.NoError(t, err, "Valid specifications are accepted.")
+}
+
+// Case: create stale feature branches matching system namespaces.
+// Where: pattern matches kube-system with and without excluding it.
+// Expected: stale feature branch is rejected unless system namespaces are excluded.
+func TestStaleFeatureBranchValidatorProtectedNamespaces(t *testing.T) {
+	// Set up data for tests.
+	validator := newValidator(t)
+
+	spec := featurebranchv2.StaleFeatureBranchSpec{
+		NamespacePattern:     "kube-*",
+		NamespacePatternType: featurebranchv2.GlobNamespacePatternType,
+		StaleAfter:           metav1.Duration{Duration: 24 * time.Hour},
+		Interval:             metav1.Duration{Duration: time.Minute},
+	}
+
+	// Testing.
+	response := validator.Handle(context.TODO(), newRequest(
+		t, admissionv1beta1.Create, "StaleFeatureBranch", newStaleFeatureBranch("stale-feature-branch", "team-a", spec),
+	))
+
+	assert.False(t, response.Allowed, "Stale feature branch matching a system namespace is rejected.")
+	assert.Contains(t, response.Result.Reason, "protected namespace kube-system", "Rejection refers to the system namespace.")
+
+	spec.ExcludeNamespaces = []string{"kube-*"}
+
+	response = validator.Handle(context.TODO(), newRequest(
+		t, admissionv1beta1.Create, "StaleFeatureBranch", newStaleFeatureBranch("stale-feature-branch", "team-a", spec),
+	))
+
+	assert.True(t, response.Allowed, "Stale feature branch excluding system namespaces is accepted.")
+}
+
+// Case: create stale feature branches with the HTTP staleness strategy.
+// Where: stale feature branch and cluster stale feature branch request an external endpoint.
+// Expected: only the cluster stale feature branch is accepted.
+func TestStaleFeatureBranchValidatorHTTPStaleness(t *testing.T) {
+	// Set up data for tests.
+	validator := newValidator(t)
+
+	spec := featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "-pr-",
+		StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+		Interval:           metav1.Duration{Duration: time.Minute},
+		Staleness: &featurebranchv2.StalenessSpec{
+			Strategies: []featurebranchv2.StalenessStrategySpec{
+				{
+					Type: featurebranchv2.HTTPStalenessStrategyType,
+					HTTP: &featurebranchv2.HTTPStalenessSpec{URL: "http://branches.ci.svc/activities/{namespace}"},
+				},
+			},
+		},
+	}
+
+	// Testing.
+	response := validator.Handle(context.TODO(), newRequest(
+		t, admissionv1beta1.Create, "StaleFeatureBranch", newStaleFeatureBranch("stale-feature-branch", "team-a", spec),
+	))
+
+	assert.False(t, response.Allowed, "Stale feature branch with the HTTP strategy is rejected.")
+	assert.Contains(t, response.Result.Reason, "cluster stale feature branches only", "Rejection refers to the strategy's scope.")
+
+	response = validator.Handle(context.TODO(), newRequest(
+		t, admissionv1beta1.Create, clusterStaleFeatureBranchKind, &featurebranchv2.ClusterStaleFeatureBranch{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: featurebranchv2.SchemeGroupVersion.String(),
+				Kind:       clusterStaleFeatureBranchKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       spec,
+		},
+	))
+
+	assert.True(t, response.Allowed, "Cluster stale feature branch with the HTTP strategy is accepted.")
+}
+
+// Case: create stale feature branches along with an existing one.
+// Where: new stale feature branches have the same criteria, overlap on an owned namespace, are in another namespace
+// or update the existing one.
+// Expected: only stale feature branches that don't conflict with the existing one are accepted.
+func TestStaleFeatureBranchValidatorOverlapping(t *testing.T) {
+	// Set up data for tests.
+	existingSpec := featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring:   "-pr-",
+		NamespacePatternType: featurebranchv2.RegexpNamespacePatternType,
+		StaleAfter:           metav1.Duration{Duration: 24 * time.Hour},
+		Interval:             metav1.Duration{Duration: time.Minute},
+	}
+
+	validator := newValidator(
+		t,
+		newStaleFeatureBranch("existing", "team-a", existingSpec),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "project-pr-1",
+			Labels: map[string]string{featurebranch.OwnerLabel: "team-a"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-b-pr-1",
+			Labels: map[string]string{featurebranch.OwnerLabel: "team-b"},
+		}},
+	)
+
+	overlappingSpec := featurebranchv2.StaleFeatureBranchSpec{
+		NamespaceSubstring: "project",
+		StaleAfter:         metav1.Duration{Duration: 24 * time.Hour},
+		Interval:           metav1.Duration{Duration: time.Minute},
+	}
+
+	validate := func(kind string, object runtime.Object, operation admissionv1beta1.Operation) admission.Response {
+		return validator.Handle(context.TODO(), newRequest(t, operation, kind, object))
+	}
+
+	// Testing.
+	response := validate("StaleFeatureBranch", newStaleFeatureBranch("same", "team-a", existingSpec), admissionv1beta1.Create)
+	assert.False(t, response.Allowed, "Stale feature branch with the same criteria is rejected.")
+	assert.Contains(t, response.Result.Reason, "same namespace criteria as stale feature branch team-a/existing", "Rejection refers to the existing stale feature branch.")
+
+	response = validate("StaleFeatureBranch", newStaleFeatureBranch("overlapping", "team-a", overlappingSpec), admissionv1beta1.Create)
+	assert.False(t, response.Allowed, "Stale feature branch overlapping on an owned namespace is rejected.")
+	assert.Contains(t, response.Result.Reason, "on namespace project-pr-1", "Rejection refers to the overlapping namespace.")
+
+	response = validate("StaleFeatureBranch", newStaleFeatureBranch("same", "team-b", existingSpec), admissionv1beta1.Create)
+	assert.True(t, response.Allowed, "Stale feature branch with the same criteria in another namespace is accepted.")
+
+	response = validate(clusterStaleFeatureBranchKind, &featurebranchv2.ClusterStaleFeatureBranch{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: featurebranchv2.SchemeGroupVersion.String(),
+			Kind:       clusterStaleFeatureBranchKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       overlappingSpec,
+	}, admissionv1beta1.Create)
+	assert.False(t, response.Allowed, "Cluster stale feature branch overlapping on a namespace is rejected.")
+
+	existingSpec.StaleAfter = metav1.Duration{Duration: 48 * time.Hour}
+
+	response = validate("StaleFeatureBranch", newStaleFeatureBranch("existing", "team-a", existingSpec), admissionv1beta1.Update)
+	assert.True(t, response.Allowed, "Stale feature branch's update isn't compared with itself.")
+
+	response = validate("StaleFeatureBranch", newStaleFeatureBranch("existing", "team-a", featurebranchv2.StaleFeatureBranchSpec{}), admissionv1beta1.Delete)
+	assert.True(t, response.Allowed, "Deletion isn't validated.")
+}
@@ -0,0 +1,58 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	featurebranchv2 "github.com/dmytrostriletskyi/stale-feature-branch-operator/pkg/apis/featurebranch/v2"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newScheme(t *testing.T) *runtime.Scheme {
+	s := runtime.NewScheme()
+
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatalf("An error occurred while registering Kubernetes schemes: (%v)", err)
+	}
+
+	if err := featurebranchv2.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("An error occurred while registering stale feature branch schemes: (%v)", err)
+	}
+
+	return s
+}
+
+func newDecoder(t *testing.T, s *runtime.Scheme) *admission.Decoder {
+	decoder, err := admission.NewDecoder(s)
+
+	if err != nil {
+		t.Fatalf("An error occurred while creating a decoder: (%v)", err)
+	}
+
+	return decoder
+}
+
+func newRequest(t *testing.T, operation admissionv1beta1.Operation, kind string, object runtime.Object) admission.Request {
+	raw, err := json.Marshal(object)
+
+	if err != nil {
+		t.Fatalf("An error occurred while marshaling an object: (%v)", err)
+	}
+
+	return admission.Request{
+		AdmissionRequest: admissionv1beta1.AdmissionRequest{
+			Operation: operation,
+			Kind: metav1.GroupVersionKind{
+				Group:   featurebranchv2.SchemeGroupVersion.Group,
+				Version: featurebranchv2.SchemeGroupVersion.Version,
+				Kind:    kind,
+			},
+			Object: runtime.RawExtension{Raw: raw},
+		},
+	}
+}